@@ -60,6 +60,12 @@ _group: "Drops": [
 		Path:   "/v1/drops/next"
 		Return: "drop.Drop"
 	},
+	#POST & {
+		Name:   "NextMatching"
+		Path:   "/v1/drops/next-matching"
+		Body:   "drop.NextBody"
+		Return: "drop.Drop"
+	},
 	#GET & {
 		Name:   "Get"
 		Path:   "/v1/drops/get/{id}"
@@ -72,12 +78,42 @@ _group: "Drops": [
 		Body:   "drop.ListBody"
 		Return: "drop.ListResponse"
 	},
+	#POST & {
+		Name:   "Search"
+		Path:   "/v1/drops/search"
+		Body:   "drop.SearchBody"
+		Return: "drop.SearchResponse"
+	},
+	#POST & {
+		Name:   "History"
+		Path:   "/v1/drops/history"
+		Body:   "drop.HistoryBody"
+		Return: "drop.HistoryResponse"
+	},
+	#POST & {
+		Name:   "Content"
+		Path:   "/v1/drops/content"
+		Body:   "drop.ContentBody"
+		Return: "drop.Content"
+	},
+	#GET & {
+		Name:   "Export"
+		Path:   "/v1/drops/export"
+		Return: "drop.Export"
+		Stream: true
+	},
 	#POST & {
 		Name:   "Create"
 		Path:   "/v1/drops/create"
 		Body:   "drop.CreateBody"
 		Return: "drop.Drop"
 	},
+	#POST & {
+		Name:   "CreateBatch"
+		Path:   "/v1/drops/create-batch"
+		Body:   "drop.CreateBatchBody"
+		Return: "drop.CreateBatchResponse"
+	},
 	#POST & {
 		Name:   "Update"
 		Path:   "/v1/drops/update"
@@ -90,10 +126,243 @@ _group: "Drops": [
 		Body:   "drop.MoveBody"
 		Return: "drop.Drop"
 	},
+	#POST & {
+		Name:   "Snooze"
+		Path:   "/v1/drops/snooze"
+		Body:   "drop.SnoozeBody"
+		Return: "drop.Drop"
+	},
+	#POST & {
+		Name:   "Pin"
+		Path:   "/v1/drops/pin"
+		Body:   "drop.PinBody"
+		Return: "drop.Drop"
+	},
+	#POST & {
+		Name:   "Unpin"
+		Path:   "/v1/drops/unpin"
+		Body:   "drop.UnpinBody"
+		Return: "drop.Drop"
+	},
 	#POST & {
 		Name:   "Delete"
 		Path:   "/v1/drops/delete"
 		Body:   "drop.DeleteBody"
 		Return: "drop.Drop"
 	},
+	#POST & {
+		Name:   "BulkMove"
+		Path:   "/v1/drops/bulk/move"
+		Body:   "drop.BulkMoveBody"
+		Return: "drop.BulkResponse"
+	},
+	#POST & {
+		Name:   "BulkTag"
+		Path:   "/v1/drops/bulk/tag"
+		Body:   "drop.BulkTagBody"
+		Return: "drop.BulkResponse"
+	},
+	#POST & {
+		Name:   "BulkDelete"
+		Path:   "/v1/drops/bulk/delete"
+		Body:   "drop.BulkDeleteBody"
+		Return: "drop.BulkResponse"
+	},
+	#GET & {
+		Name:   "Trash"
+		Path:   "/v1/drops/trash"
+		Return: "drop.TrashResponse"
+	},
+	#POST & {
+		Name:   "Restore"
+		Path:   "/v1/drops/restore"
+		Body:   "drop.RestoreBody"
+		Return: "drop.Drop"
+	},
+	#POST & {
+		Name:   "EmptyTrash"
+		Path:   "/v1/drops/empty-trash"
+		Body:   "drop.EmptyTrashBody"
+		Return: "drop.EmptyTrashResponse"
+	},
+	#POST & {
+		Name:   "AddHighlight"
+		Path:   "/v1/drops/highlights/add"
+		Body:   "drop.AddHighlightBody"
+		Return: "drop.Highlight"
+	},
+	#POST & {
+		Name:   "EditHighlight"
+		Path:   "/v1/drops/highlights/edit"
+		Body:   "drop.EditHighlightBody"
+		Return: "drop.Highlight"
+	},
+	#POST & {
+		Name:   "RemoveHighlight"
+		Path:   "/v1/drops/highlights/remove"
+		Body:   "drop.RemoveHighlightBody"
+		Return: "drop.Highlight"
+	},
+]
+
+_group: "Tags": [
+	#POST & {
+		Name:   "Create"
+		Path:   "/v1/tags/create"
+		Body:   "tag.CreateBody"
+		Return: "tag.Tag"
+	},
+	#POST & {
+		Name:   "List"
+		Path:   "/v1/tags/list"
+		Body:   "tag.ListBody"
+		Return: "tag.ListResponse"
+	},
+	#GET & {
+		Name:   "Get"
+		Path:   "/v1/tags/get/{id}"
+		Params: "tag.GetParams"
+		Return: "tag.Tag"
+	},
+	#POST & {
+		Name:   "Rename"
+		Path:   "/v1/tags/rename"
+		Body:   "tag.RenameBody"
+		Return: "tag.Tag"
+	},
+	#POST & {
+		Name:   "Update"
+		Path:   "/v1/tags/update"
+		Body:   "tag.UpdateBody"
+		Return: "tag.Tag"
+	},
+	#POST & {
+		Name:   "SetParent"
+		Path:   "/v1/tags/set-parent"
+		Body:   "tag.SetParentBody"
+		Return: "tag.Tag"
+	},
+	#POST & {
+		Name:   "Merge"
+		Path:   "/v1/tags/merge"
+		Body:   "tag.MergeBody"
+		Return: "tag.MergeResponse"
+	},
+	#POST & {
+		Name:   "Delete"
+		Path:   "/v1/tags/delete"
+		Body:   "tag.DeleteBody"
+		Return: "tag.Tag"
+	},
+]
+
+_group: "Stats": [
+	#POST & {
+		Name:   "Get"
+		Path:   "/v1/stats"
+		Body:   "stats.Body"
+		Return: "stats.Stats"
+	},
+]
+
+_group: "Policies": [
+	#POST & {
+		Name:   "Create"
+		Path:   "/v1/policies/create"
+		Body:   "policy.CreateBody"
+		Return: "policy.Policy"
+	},
+	#GET & {
+		Name:   "List"
+		Path:   "/v1/policies/list"
+		Return: "policy.ListResponse"
+	},
+	#POST & {
+		Name:   "Update"
+		Path:   "/v1/policies/update"
+		Body:   "policy.UpdateBody"
+		Return: "policy.Policy"
+	},
+	#POST & {
+		Name:   "Delete"
+		Path:   "/v1/policies/delete"
+		Body:   "policy.DeleteBody"
+		Return: "policy.Policy"
+	},
+	#POST & {
+		Name:   "Preview"
+		Path:   "/v1/policies/preview"
+		Body:   "policy.PreviewBody"
+		Return: "policy.PreviewResponse"
+	},
+]
+
+_group: "Views": [
+	#POST & {
+		Name:   "Create"
+		Path:   "/v1/views/create"
+		Body:   "view.CreateBody"
+		Return: "view.View"
+	},
+	#GET & {
+		Name:   "List"
+		Path:   "/v1/views/list"
+		Return: "view.ListResponse"
+	},
+	#POST & {
+		Name:   "Update"
+		Path:   "/v1/views/update"
+		Body:   "view.UpdateBody"
+		Return: "view.View"
+	},
+	#POST & {
+		Name:   "Delete"
+		Path:   "/v1/views/delete"
+		Body:   "view.DeleteBody"
+		Return: "view.View"
+	},
+	#POST & {
+		Name:   "ListDrops"
+		Path:   "/v1/views/drops/list"
+		Body:   "view.ListDropsBody"
+		Return: "drop.ListResponse"
+	},
+	#POST & {
+		Name:   "NextDrop"
+		Path:   "/v1/views/drops/next"
+		Body:   "view.NextDropBody"
+		Return: "drop.Drop"
+	},
+]
+
+_group: "Rules": [
+	#POST & {
+		Name:   "Create"
+		Path:   "/v1/rules/create"
+		Body:   "rule.CreateBody"
+		Return: "rule.Rule"
+	},
+	#GET & {
+		Name:   "List"
+		Path:   "/v1/rules/list"
+		Return: "rule.ListResponse"
+	},
+	#POST & {
+		Name:   "Update"
+		Path:   "/v1/rules/update"
+		Body:   "rule.UpdateBody"
+		Return: "rule.Rule"
+	},
+	#POST & {
+		Name:   "Delete"
+		Path:   "/v1/rules/delete"
+		Body:   "rule.DeleteBody"
+		Return: "rule.Rule"
+	},
+	#POST & {
+		Name:   "Apply"
+		Path:   "/v1/rules/apply"
+		Body:   "rule.ApplyBody"
+		Return: "rule.ApplyResponse"
+	},
 ]
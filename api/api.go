@@ -126,6 +126,32 @@ func (s *Server) Respond(w http.ResponseWriter, v interface{}, err error) {
 		panic(werr)
 	}
 }
+
+// A Streamer writes a response body incrementally, for responses too large to
+// build in memory first.
+type Streamer interface {
+	ContentType() string
+	Stream(w io.Writer) error
+}
+
+// Stream writes the streaming response and then calls done (usually to commit
+// the request transaction). Once the stream has started, the status code has
+// already been sent, so errors can only be logged. Streamers need to end with
+// something like a trailer record so clients can tell when a stream was cut
+// short.
+func (s *Server) Stream(w http.ResponseWriter, v Streamer, done func() error) {
+	w.Header().Set("Content-Type", v.ContentType())
+	w.WriteHeader(http.StatusOK)
+
+	err := v.Stream(w)
+	if err == nil {
+		err = done()
+	}
+	if err != nil {
+		s.log.Error("Could not finish streaming response", zap.Error(err))
+	}
+}
+
 func writeError(log *zap.Logger, w http.ResponseWriter, err error) error {
 	var e Error
 	if !errors.As(err, &e) {
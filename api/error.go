@@ -12,6 +12,11 @@ type Error struct {
 	Status  httpStatus `json:"status"`
 	Code    ErrorCode  `json:"code"`
 	Message string     `json:"message"`
+
+	// Details is extra information for clients to handle the error, like the
+	// existing resource in a DuplicateError. This should be a pointer or nil
+	// so that Errors stay comparable.
+	Details interface{} `json:"details,omitempty"`
 }
 
 func (e Error) Error() string {
@@ -62,3 +67,14 @@ func NoResourceError(resource, id string) Error {
 		Message: fmt.Sprintf(`No %s found with id "%s".`, resource, id),
 	}
 }
+
+// DuplicateError reports that the resource being created already exists. The
+// existing resource is included in the details.
+func DuplicateError(resource, id string, existing interface{}) Error {
+	return Error{
+		Status:  http.StatusConflict,
+		Code:    "duplicate_resource",
+		Message: fmt.Sprintf(`A matching %s already exists with id "%s".`, resource, id),
+		Details: existing,
+	}
+}
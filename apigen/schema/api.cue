@@ -19,6 +19,10 @@ package schema
 
 	Authenticated: bool | *true
 
+	// Streaming routes write their response incrementally instead of
+	// encoding one value. The Return type must implement api.Streamer.
+	Stream: bool | *false
+
 	Path:    string
 	Method:  #HttpMethod
 	Params?: string
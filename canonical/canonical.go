@@ -0,0 +1,156 @@
+// Package canonical normalizes URLs so that different links to the same page
+// compare equal.
+package canonical
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var ErrNotAbsolute = errors.New("URL must be absolute")
+
+// A Rule adjusts URLs for one host, or for every host if Host is empty.
+type Rule struct {
+	// Host matches the URL's host and all of its subdomains.
+	Host string `mapstructure:"host"`
+
+	// StripParams lists query parameters to remove. A name ending in "*"
+	// matches every parameter with that prefix.
+	StripParams []string `mapstructure:"strip-params"`
+
+	// KeepParams, if not empty, removes every query parameter not in the list.
+	// This is the easiest way to handle sites with lots of tracking junk but
+	// only one meaningful parameter (like YouTube's "v").
+	KeepParams []string `mapstructure:"keep-params"`
+
+	// KeepFragment keeps the fragment, for sites that use it for routing.
+	KeepFragment bool `mapstructure:"keep-fragment"`
+}
+
+// DefaultRules strip the tracking parameters that marketing tools, social
+// networks, and newsletters add to links.
+var DefaultRules = Rules{
+	{
+		StripParams: []string{
+			"utm_*",
+			"fbclid",
+			"gclid",
+			"dclid",
+			"msclkid",
+			"yclid",
+			"igshid",
+			"mc_cid",
+			"mc_eid",
+			"_hsenc",
+			"_hsmi",
+			"mkt_tok",
+			"ref_src",
+		},
+	},
+}
+
+// Rules is a list of rules to apply in addition to DefaultRules. The zero value
+// applies only the defaults.
+type Rules []Rule
+
+// URL returns the canonical form of rawURL:
+//
+// - The scheme and host are lowercased, and default ports are removed.
+// - An empty path becomes "/".
+// - Query parameters are filtered by the rules and then sorted.
+// - The fragment is removed, unless a rule says to keep it.
+func (rs Rules) URL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", ErrNotAbsolute
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = host(u)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	keepFragment := false
+	query := u.Query()
+	rules := make(Rules, 0, len(DefaultRules)+len(rs))
+	rules = append(rules, DefaultRules...)
+	rules = append(rules, rs...)
+	for _, r := range rules {
+		if !r.matches(u.Hostname()) {
+			continue
+		}
+		for name := range query {
+			if r.strips(name) {
+				query.Del(name)
+			}
+		}
+		keepFragment = keepFragment || r.KeepFragment
+	}
+	// Encode sorts by key, so parameter order doesn't matter.
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	if !keepFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+	return u.String(), nil
+}
+
+// host lowercases the host and removes the port if it's the default for the
+// scheme.
+func host(u *url.URL) string {
+	h := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port == "" {
+		// Hostname strips the brackets from IPv6 addresses, and they're
+		// needed to be unambiguous even without a port.
+		if strings.Contains(h, ":") {
+			return "[" + h + "]"
+		}
+		return h
+	}
+	return net.JoinHostPort(h, port)
+}
+
+func (r Rule) matches(host string) bool {
+	if r.Host == "" {
+		return true
+	}
+	want := strings.ToLower(r.Host)
+	return host == want || strings.HasSuffix(host, "."+want)
+}
+
+func (r Rule) strips(name string) bool {
+	if len(r.KeepParams) > 0 && !contains(r.KeepParams, name) {
+		return true
+	}
+	for _, p := range r.StripParams {
+		if prefix := strings.TrimSuffix(p, "*"); prefix != p {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == p {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
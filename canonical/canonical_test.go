@@ -0,0 +1,47 @@
+package canonical_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/canonical"
+)
+
+func TestURL(t *testing.T) {
+	rules := canonical.Rules{
+		{Host: "youtube.com", KeepParams: []string{"v"}},
+		{Host: "example.org", StripParams: []string{"session"}, KeepFragment: true},
+	}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://example.com", "https://example.com/"},
+		{"HTTPS://Example.COM:443/Path", "https://example.com/Path"},
+		{"http://example.com:80/a", "http://example.com/a"},
+		{"http://example.com:8080/a", "http://example.com:8080/a"},
+		{"https://example.com/a#section", "https://example.com/a"},
+		{"https://example.com/a?", "https://example.com/a"},
+		{"https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"https://example.com/a?utm_source=x&utm_medium=y&id=3&fbclid=z", "https://example.com/a?id=3"},
+		{"https://www.youtube.com/watch?v=abc&list=xyz&t=10", "https://www.youtube.com/watch?v=abc"},
+		{"https://notyoutube.com/watch?v=abc&list=xyz", "https://notyoutube.com/watch?list=xyz&v=abc"},
+		{"https://example.org/app?session=1#/inbox", "https://example.org/app#/inbox"},
+		{"https://[::1]:443/", "https://[::1]/"},
+	}
+	for _, tt := range tests {
+		got, err := rules.URL(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestURLNotAbsolute(t *testing.T) {
+	for _, in := range []string{"/relative", "example.com/a", "javascript:alert(1)"} {
+		_, err := canonical.Rules(nil).URL(in)
+		assert.ErrorIs(t, err, canonical.ErrNotAbsolute, in)
+	}
+}
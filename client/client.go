@@ -85,6 +85,16 @@ func parse(r *http.Response, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(&v)
 }
 
+// stream returns the response body for the caller to read and close. If the
+// response is an HTTP error, stream instead parses and returns the api.Error.
+func stream(r *http.Response) (io.ReadCloser, error) {
+	if r.StatusCode >= 400 {
+		defer r.Body.Close()
+		return nil, parse(r, nil)
+	}
+	return r.Body, nil
+}
+
 func (c Client) Get(ctx context.Context, path string) (*http.Response, error) {
 	url, err := c.baseURL.Parse(path)
 	if err != nil {
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+// DropIterator walks every page of a drop listing, fetching the next page
+// whenever the current one runs out.
+//
+//	it := c.Drops.Iter(ctx, body)
+//	for it.Next() {
+//		d := it.Drop()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type DropIterator struct {
+	ctx   context.Context
+	drops Drops
+	body  drop.ListBody
+
+	page []drop.Drop
+	cur  drop.Drop
+	done bool
+	err  error
+}
+
+// Iter returns an iterator over all drops matching the list body, starting
+// from body.Cursor (if set).
+func (g Drops) Iter(ctx context.Context, body drop.ListBody) *DropIterator {
+	return &DropIterator{
+		ctx:   ctx,
+		drops: g,
+		body:  body,
+	}
+}
+
+// Next advances to the next drop, fetching a new page if needed. It returns
+// false when there are no more drops or an error occurred.
+func (it *DropIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *DropIterator) fetch() {
+	res, err := it.drops.List(it.ctx, it.body)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.page = res.Drops
+	it.body.Cursor = res.NextCursor
+	it.done = res.NextCursor == nil
+}
+
+// Drop returns the drop the iterator is currently pointing at.
+func (it *DropIterator) Drop() drop.Drop {
+	return it.cur
+}
+
+// Err returns the first error encountered while fetching pages.
+func (it *DropIterator) Err() error {
+	return it.err
+}
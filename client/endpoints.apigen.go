@@ -13,6 +13,11 @@ import (
 	"github.com/metagram-net/firehose/api"
 	"github.com/metagram-net/firehose/auth"
 	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/policy"
+	"github.com/metagram-net/firehose/rule"
+	"github.com/metagram-net/firehose/stats"
+	"github.com/metagram-net/firehose/tag"
+	"github.com/metagram-net/firehose/view"
 	"github.com/metagram-net/firehose/wellknown"
 )
 
@@ -25,6 +30,11 @@ type Endpoints struct {
 	WellKnown WellKnown
 	Auth      Auth
 	Drops     Drops
+	Tags      Tags
+	Stats     Stats
+	Policies  Policies
+	Views     Views
+	Rules     Rules
 }
 
 func NewEndpoints(f Fetcher) Endpoints {
@@ -32,6 +42,11 @@ func NewEndpoints(f Fetcher) Endpoints {
 		WellKnown: WellKnown{f},
 		Auth:      Auth{f},
 		Drops:     Drops{f},
+		Tags:      Tags{f},
+		Stats:     Stats{f},
+		Policies:  Policies{f},
+		Views:     Views{f},
+		Rules:     Rules{f},
 	}
 }
 
@@ -86,6 +101,23 @@ func (g Drops) Next(ctx context.Context) (drop.Drop, error) {
 	return val, parse(res, &val)
 }
 
+func (g Drops) NextMatching(ctx context.Context, body drop.NextBody) (drop.Drop, error) {
+	var val drop.Drop
+
+	path := "/v1/drops/next-matching"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
 func (g Drops) Get(ctx context.Context, params drop.GetParams) (drop.Drop, error) {
 	var val drop.Drop
 
@@ -120,6 +152,70 @@ func (g Drops) List(ctx context.Context, body drop.ListBody) (drop.ListResponse,
 	return val, parse(res, &val)
 }
 
+func (g Drops) Search(ctx context.Context, body drop.SearchBody) (drop.SearchResponse, error) {
+	var val drop.SearchResponse
+
+	path := "/v1/drops/search"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) History(ctx context.Context, body drop.HistoryBody) (drop.HistoryResponse, error) {
+	var val drop.HistoryResponse
+
+	path := "/v1/drops/history"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) Content(ctx context.Context, body drop.ContentBody) (drop.Content, error) {
+	var val drop.Content
+
+	path := "/v1/drops/content"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) Export(ctx context.Context) (io.ReadCloser, error) {
+	var val io.ReadCloser
+
+	path := "/v1/drops/export"
+
+	res, err := g.f.Get(ctx, path)
+	if err != nil {
+		return val, err
+	}
+
+	return stream(res)
+}
+
 func (g Drops) Create(ctx context.Context, body drop.CreateBody) (drop.Drop, error) {
 	var val drop.Drop
 
@@ -137,6 +233,23 @@ func (g Drops) Create(ctx context.Context, body drop.CreateBody) (drop.Drop, err
 	return val, parse(res, &val)
 }
 
+func (g Drops) CreateBatch(ctx context.Context, body drop.CreateBatchBody) (drop.CreateBatchResponse, error) {
+	var val drop.CreateBatchResponse
+
+	path := "/v1/drops/create-batch"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
 func (g Drops) Update(ctx context.Context, body drop.UpdateBody) (drop.Drop, error) {
 	var val drop.Drop
 
@@ -171,6 +284,57 @@ func (g Drops) Move(ctx context.Context, body drop.MoveBody) (drop.Drop, error)
 	return val, parse(res, &val)
 }
 
+func (g Drops) Snooze(ctx context.Context, body drop.SnoozeBody) (drop.Drop, error) {
+	var val drop.Drop
+
+	path := "/v1/drops/snooze"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) Pin(ctx context.Context, body drop.PinBody) (drop.Drop, error) {
+	var val drop.Drop
+
+	path := "/v1/drops/pin"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) Unpin(ctx context.Context, body drop.UnpinBody) (drop.Drop, error) {
+	var val drop.Drop
+
+	path := "/v1/drops/unpin"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
 func (g Drops) Delete(ctx context.Context, body drop.DeleteBody) (drop.Drop, error) {
 	var val drop.Drop
 
@@ -187,3 +351,585 @@ func (g Drops) Delete(ctx context.Context, body drop.DeleteBody) (drop.Drop, err
 
 	return val, parse(res, &val)
 }
+
+func (g Drops) BulkMove(ctx context.Context, body drop.BulkMoveBody) (drop.BulkResponse, error) {
+	var val drop.BulkResponse
+
+	path := "/v1/drops/bulk/move"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) BulkTag(ctx context.Context, body drop.BulkTagBody) (drop.BulkResponse, error) {
+	var val drop.BulkResponse
+
+	path := "/v1/drops/bulk/tag"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) BulkDelete(ctx context.Context, body drop.BulkDeleteBody) (drop.BulkResponse, error) {
+	var val drop.BulkResponse
+
+	path := "/v1/drops/bulk/delete"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) Trash(ctx context.Context) (drop.TrashResponse, error) {
+	var val drop.TrashResponse
+
+	path := "/v1/drops/trash"
+
+	res, err := g.f.Get(ctx, path)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) Restore(ctx context.Context, body drop.RestoreBody) (drop.Drop, error) {
+	var val drop.Drop
+
+	path := "/v1/drops/restore"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) EmptyTrash(ctx context.Context, body drop.EmptyTrashBody) (drop.EmptyTrashResponse, error) {
+	var val drop.EmptyTrashResponse
+
+	path := "/v1/drops/empty-trash"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) AddHighlight(ctx context.Context, body drop.AddHighlightBody) (drop.Highlight, error) {
+	var val drop.Highlight
+
+	path := "/v1/drops/highlights/add"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) EditHighlight(ctx context.Context, body drop.EditHighlightBody) (drop.Highlight, error) {
+	var val drop.Highlight
+
+	path := "/v1/drops/highlights/edit"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Drops) RemoveHighlight(ctx context.Context, body drop.RemoveHighlightBody) (drop.Highlight, error) {
+	var val drop.Highlight
+
+	path := "/v1/drops/highlights/remove"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+type Tags struct {
+	f Fetcher
+}
+
+func (g Tags) Create(ctx context.Context, body tag.CreateBody) (tag.Tag, error) {
+	var val tag.Tag
+
+	path := "/v1/tags/create"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) List(ctx context.Context, body tag.ListBody) (tag.ListResponse, error) {
+	var val tag.ListResponse
+
+	path := "/v1/tags/list"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) Get(ctx context.Context, params tag.GetParams) (tag.Tag, error) {
+	var val tag.Tag
+
+	url, err := (&mux.Route{}).Path("/v1/tags/get/{id}").URL(api.Pairs(params)...)
+	if err != nil {
+		return val, err
+	}
+	path := url.String()
+
+	res, err := g.f.Get(ctx, path)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) Rename(ctx context.Context, body tag.RenameBody) (tag.Tag, error) {
+	var val tag.Tag
+
+	path := "/v1/tags/rename"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) Update(ctx context.Context, body tag.UpdateBody) (tag.Tag, error) {
+	var val tag.Tag
+
+	path := "/v1/tags/update"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) SetParent(ctx context.Context, body tag.SetParentBody) (tag.Tag, error) {
+	var val tag.Tag
+
+	path := "/v1/tags/set-parent"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) Merge(ctx context.Context, body tag.MergeBody) (tag.MergeResponse, error) {
+	var val tag.MergeResponse
+
+	path := "/v1/tags/merge"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Tags) Delete(ctx context.Context, body tag.DeleteBody) (tag.Tag, error) {
+	var val tag.Tag
+
+	path := "/v1/tags/delete"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+type Stats struct {
+	f Fetcher
+}
+
+func (g Stats) Get(ctx context.Context, body stats.Body) (stats.Stats, error) {
+	var val stats.Stats
+
+	path := "/v1/stats"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+type Policies struct {
+	f Fetcher
+}
+
+func (g Policies) Create(ctx context.Context, body policy.CreateBody) (policy.Policy, error) {
+	var val policy.Policy
+
+	path := "/v1/policies/create"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Policies) List(ctx context.Context) (policy.ListResponse, error) {
+	var val policy.ListResponse
+
+	path := "/v1/policies/list"
+
+	res, err := g.f.Get(ctx, path)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Policies) Update(ctx context.Context, body policy.UpdateBody) (policy.Policy, error) {
+	var val policy.Policy
+
+	path := "/v1/policies/update"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Policies) Delete(ctx context.Context, body policy.DeleteBody) (policy.Policy, error) {
+	var val policy.Policy
+
+	path := "/v1/policies/delete"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Policies) Preview(ctx context.Context, body policy.PreviewBody) (policy.PreviewResponse, error) {
+	var val policy.PreviewResponse
+
+	path := "/v1/policies/preview"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+type Views struct {
+	f Fetcher
+}
+
+func (g Views) Create(ctx context.Context, body view.CreateBody) (view.View, error) {
+	var val view.View
+
+	path := "/v1/views/create"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Views) List(ctx context.Context) (view.ListResponse, error) {
+	var val view.ListResponse
+
+	path := "/v1/views/list"
+
+	res, err := g.f.Get(ctx, path)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Views) Update(ctx context.Context, body view.UpdateBody) (view.View, error) {
+	var val view.View
+
+	path := "/v1/views/update"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Views) Delete(ctx context.Context, body view.DeleteBody) (view.View, error) {
+	var val view.View
+
+	path := "/v1/views/delete"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Views) ListDrops(ctx context.Context, body view.ListDropsBody) (drop.ListResponse, error) {
+	var val drop.ListResponse
+
+	path := "/v1/views/drops/list"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Views) NextDrop(ctx context.Context, body view.NextDropBody) (drop.Drop, error) {
+	var val drop.Drop
+
+	path := "/v1/views/drops/next"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+type Rules struct {
+	f Fetcher
+}
+
+func (g Rules) Create(ctx context.Context, body rule.CreateBody) (rule.Rule, error) {
+	var val rule.Rule
+
+	path := "/v1/rules/create"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Rules) List(ctx context.Context) (rule.ListResponse, error) {
+	var val rule.ListResponse
+
+	path := "/v1/rules/list"
+
+	res, err := g.f.Get(ctx, path)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Rules) Update(ctx context.Context, body rule.UpdateBody) (rule.Rule, error) {
+	var val rule.Rule
+
+	path := "/v1/rules/update"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Rules) Delete(ctx context.Context, body rule.DeleteBody) (rule.Rule, error) {
+	var val rule.Rule
+
+	path := "/v1/rules/delete"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
+
+func (g Rules) Apply(ctx context.Context, body rule.ApplyBody) (rule.ApplyResponse, error) {
+	var val rule.ApplyResponse
+
+	path := "/v1/rules/apply"
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return val, err
+	}
+	res, err := g.f.Post(ctx, path, &b)
+	if err != nil {
+		return val, err
+	}
+
+	return val, parse(res, &val)
+}
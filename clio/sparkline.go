@@ -0,0 +1,27 @@
+package clio
+
+import "strings"
+
+var ticks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the values as a row of bars, scaled so the largest value
+// is a full-height bar. Zeros get the shortest bar, so the row always has
+// one bar per value.
+func Sparkline(values []int64) string {
+	var max int64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 && v > 0 {
+			i = int((v*int64(len(ticks)-1) + max - 1) / max)
+		}
+		b.WriteRune(ticks[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package clio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metagram-net/firehose/clio"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"zeros", []int64{0, 0, 0}, "▁▁▁"},
+		{"scaled", []int64{0, 1, 2, 4, 7}, "▁▂▃▅█"},
+		{"flat", []int64{5, 5}, "██"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, clio.Sparkline(tt.values))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package clio
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Wrap breaks the lines of text so they fit in width columns, where possible.
+// Lines are only broken between words, so longer words overflow.
+//
+// Indented lines are assumed to be preformatted and left alone. Lines that
+// start with a list marker or quote marker keep it to the left of the
+// wrapped text.
+func Wrap(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l == "" || l[0] == ' ' || l[0] == '\t' {
+			out = append(out, l)
+			continue
+		}
+		out = append(out, wrapLine(l, width)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+func wrapLine(line string, width int) []string {
+	marker := leader(line)
+	indent := strings.Repeat(" ", utf8.RuneCountInString(marker))
+	if strings.HasPrefix(marker, ">") {
+		// Quotes are marked on every line, not just the first.
+		indent = marker
+	}
+
+	var (
+		lines []string
+		cur   = marker
+		n     = utf8.RuneCountInString(marker)
+		empty = true
+	)
+	for _, word := range strings.Fields(line[len(marker):]) {
+		w := utf8.RuneCountInString(word)
+		if !empty && n+1+w > width {
+			lines = append(lines, cur)
+			cur, n, empty = indent, utf8.RuneCountInString(indent), true
+		}
+		if !empty {
+			cur += " "
+			n++
+		}
+		cur += word
+		n += w
+		empty = false
+	}
+	return append(lines, cur)
+}
+
+// leader returns the list and quote markers at the start of line, like "- "
+// or "> 1. ".
+func leader(line string) string {
+	i := 0
+	for {
+		rest := line[i:]
+		switch {
+		case strings.HasPrefix(rest, "> "), strings.HasPrefix(rest, "- "):
+			i += 2
+			continue
+		}
+		digits := 0
+		for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+			digits++
+		}
+		if digits > 0 && strings.HasPrefix(rest[digits:], ". ") {
+			i += digits + 2
+			continue
+		}
+		return line[:i]
+	}
+}
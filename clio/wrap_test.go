@@ -0,0 +1,32 @@
+package clio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metagram-net/firehose/clio"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{"short", "fits fine", 20, "fits fine"},
+		{"wrapped", "the quick brown fox jumps over the lazy dog", 15, "the quick brown\nfox jumps over\nthe lazy dog"},
+		{"long word", "a supercalifragilistic word", 10, "a\nsupercalifragilistic\nword"},
+		{"paragraphs", "one two three\n\nfour five", 8, "one two\nthree\n\nfour\nfive"},
+		{"list", "- one two three four", 10, "- one two\n  three\n  four"},
+		{"numbered", "12. one two three", 10, "12. one\n    two\n    three"},
+		{"quote", "> one two three four", 10, "> one two\n> three\n> four"},
+		{"preformatted", "    if x { return y }", 8, "    if x { return y }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, clio.Wrap(tt.text, tt.width))
+		})
+	}
+}
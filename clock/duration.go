@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidDuration = errors.New("invalid duration")
+
+var durationPart = regexp.MustCompile(`^(\d+)(w|d|h|m|s)`)
+
+var durationUnits = map[string]time.Duration{
+	"w": 7 * 24 * time.Hour,
+	"d": 24 * time.Hour,
+	"h": time.Hour,
+	"m": time.Minute,
+	"s": time.Second,
+}
+
+// ParseDuration parses a duration like "3d" or "1w2d12h". It's like
+// time.ParseDuration, but supports days and weeks (which are always 24 hours
+// and 7 days) and only whole numbers of each unit.
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	var d time.Duration
+	for rest := s; rest != ""; {
+		m := durationPart.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		d += time.Duration(n) * durationUnits[m[2]]
+		rest = rest[len(m[0]):]
+	}
+	return d, nil
+}
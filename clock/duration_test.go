@@ -0,0 +1,31 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/clock"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"3d":       3 * 24 * time.Hour,
+		"1w":       7 * 24 * time.Hour,
+		"1w2d12h":  9*24*time.Hour + 12*time.Hour,
+		"90m":      90 * time.Minute,
+		"1h30m15s": time.Hour + 30*time.Minute + 15*time.Second,
+	}
+	for in, want := range tests {
+		got, err := clock.ParseDuration(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "3", "d", "1.5d", "3y", "-1d", "1d "} {
+		_, err := clock.ParseDuration(in)
+		assert.ErrorIs(t, err, clock.ErrInvalidDuration, in)
+	}
+}
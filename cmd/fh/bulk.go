@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/moray"
+)
+
+var (
+	errNoIDs      = errors.New("no drop IDs given: use --id or pipe them to stdin")
+	errBulkFailed = errors.New("some drops could not be changed")
+)
+
+// dropIDs returns the IDs from the --id flags. If there aren't any, it reads
+// IDs from stdin instead, separated by whitespace, so the output of other
+// commands can be piped in.
+func dropIDs(flags moray.UUIDs) ([]uuid.UUID, error) {
+	if len(flags) > 0 {
+		return flags, nil
+	}
+
+	// Don't wait for someone to type IDs in.
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&os.ModeCharDevice != 0 {
+		return nil, errNoIDs
+	}
+
+	var ids []uuid.UUID
+	s := bufio.NewScanner(os.Stdin)
+	s.Split(bufio.ScanWords)
+	for s.Scan() {
+		id, err := uuid.FromString(s.Text())
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errNoIDs
+	}
+	return ids, nil
+}
+
+// encodeBulk prints the results of a bulk operation, and returns an error if
+// any of the drops failed (in which case none of them were changed).
+func encodeBulk(res drop.BulkResponse) error {
+	if err := json.NewEncoder(os.Stdout).Encode(res); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range res.Results {
+		if r.Error != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d failed, so none were changed", errBulkFailed, failed, len(res.Results))
+	}
+	return nil
+}
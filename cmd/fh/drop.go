@@ -1,14 +1,27 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/metagram-net/firehose/clio"
+	"github.com/metagram-net/firehose/clock"
 	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/exporter"
+	"github.com/metagram-net/firehose/importer"
 	"github.com/metagram-net/firehose/moray"
 	"github.com/metagram-net/firehose/null"
+	"github.com/metagram-net/firehose/tag"
 )
 
 func dropCmd() *cobra.Command {
@@ -18,12 +31,24 @@ func dropCmd() *cobra.Command {
 	}
 	cmd.AddCommand(
 		dropNewCmd(),
+		dropImportCmd(),
+		dropExportCmd(),
 		dropGetCmd(),
 		dropNextCmd(),
 		dropListCmd(),
+		dropSearchCmd(),
+		dropHistoryCmd(),
+		dropReadCmd(),
 		dropEditCmd(),
+		dropNotesCmd(),
+		dropHighlightCmd(),
 		dropMoveCmd(),
+		dropTagCmd(),
+		dropSnoozeCmd(),
+		dropPinCmd(),
+		dropUnpinCmd(),
 		dropDeleteCmd(),
+		dropTrashCmd(),
 	)
 	return cmd
 }
@@ -55,20 +80,167 @@ func dropNewCmd() *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&body.Title, "title", "", "Set the title")
 	flags.StringVar(&body.URL, "url", "", "Set the URL")
+	flags.StringArrayVar(&body.TagNames, "tag", nil, "Tag the drop, creating the tag if it doesn't exist (repeatable)")
+	flags.BoolVar(&body.Bump, "bump", false, "If the URL was already dropped, move that drop back to unread")
 	cmd.MarkFlagRequired("url")
 	return cmd
 }
 
+func dropImportCmd() *cobra.Command {
+	var args struct {
+		Format    importer.Format `flag:"format" usage:"The export file format (default: detect from the file)"`
+		BatchSize null.Int32      `flag:"batch-size" usage:"The number of drops to create per request (default: the server maximum)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "import FILE",
+		Short:        "Import drops from a Pocket, Instapaper, Netscape bookmark, or Markdown export",
+		Long:         "Import drops from a Pocket, Instapaper, Netscape bookmark, or Markdown export. Large files are sent in batches, and each batch is saved as soon as it's sent. Drops that already exist are skipped, so it's safe to run the import again if a batch fails.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, argv []string) error {
+			ctx := cmd.Context()
+
+			size := drop.MaxBatchSize
+			if n := args.BatchSize.Ptr(); n != nil {
+				size = int(*n)
+			}
+			if size <= 0 || size > drop.MaxBatchSize {
+				return fmt.Errorf("%w: must be between 1 and %d", errBatchSize, drop.MaxBatchSize)
+			}
+
+			f, err := os.Open(argv[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r := bufio.NewReader(f)
+
+			format := args.Format
+			if format == "" {
+				// Peek returns an error if the file is shorter than this, but
+				// the bytes it did read are still good for detection.
+				head, _ := r.Peek(1024)
+				format, err = importer.Detect(f.Name(), head)
+				if err != nil {
+					return err
+				}
+			}
+
+			items, err := importer.Parse(r, format)
+			if err != nil {
+				return err
+			}
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			for start := 0; start < len(items); start += size {
+				end := start + size
+				if end > len(items) {
+					end = len(items)
+				}
+
+				res, err := c.Drops.CreateBatch(ctx, drop.CreateBatchBody{
+					Drops: items[start:end],
+				})
+				if err != nil {
+					// Earlier batches are already saved. Running the import
+					// again skips them as duplicates.
+					return fmt.Errorf("import drops %d-%d of %d (%d already imported, run again to retry): %w", start+1, end, len(items), start, err)
+				}
+				for _, d := range res.Drops {
+					if err := enc.Encode(d); err != nil {
+						return err
+					}
+				}
+				fmt.Fprintf(os.Stderr, "Imported drops %d-%d of %d\n", start+1, end, len(items))
+			}
+			return nil
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+var errBatchSize = errors.New("invalid batch size")
+
+func dropExportCmd() *cobra.Command {
+	var args struct {
+		Format exporter.Format `flag:"format" usage:"The export format: jsonl, html, opml, or markdown (default: jsonl)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "export PATH",
+		Short:        "Export all drops to a file (use - for stdout), or a directory for markdown",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, argv []string) error {
+			ctx := cmd.Context()
+
+			format := args.Format
+			if format == "" {
+				format = exporter.FormatJSONL
+			}
+
+			var w exporter.Writer
+			path := argv[0]
+			if format == exporter.FormatMarkdown {
+				if path == "-" {
+					return fmt.Errorf("%w: %s", exporter.ErrDirectoryFormat, format)
+				}
+				w = exporter.NewMarkdownWriter(path)
+			} else {
+				var out io.Writer = os.Stdout
+				if path != "-" {
+					f, err := os.Create(path)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					out = f
+				}
+
+				var err error
+				w, err = exporter.NewWriter(out, format)
+				if err != nil {
+					return err
+				}
+			}
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body, err := c.Drops.Export(ctx)
+			if err != nil {
+				return err
+			}
+			defer body.Close()
+
+			return exporter.Copy(w, body)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
 // TODO: extract the pattern for codegen
 // res, err := fn(cmd.Context(), Client(), args)
 // moray.Exit(io, res, err)
 
 func dropEditCmd() *cobra.Command {
 	var args struct {
-		ID    null.UUID   `flag:"id,required" usage:"The Drop ID"`
-		Title null.String `flag:"title" usage:"Set the title"`
-		URL   null.String `flag:"url" usage:"Set the URL"`
-		Tags  moray.UUIDs `flag:"tags" usage:"Set the tags"`
+		ID       null.UUID     `flag:"id,required" usage:"The Drop ID"`
+		Title    null.String   `flag:"title" usage:"Set the title"`
+		URL      null.String   `flag:"url" usage:"Set the URL"`
+		Tags     moray.UUIDs   `flag:"tags" usage:"Set the tags"`
+		TagNames moray.Strings `flag:"tag" usage:"Set the tags by name, creating any that don't exist (repeatable)"`
+		Notes    null.String   `flag:"notes" usage:"Set the notes"`
 	}
 
 	cmd := &cobra.Command{
@@ -84,16 +256,68 @@ func dropEditCmd() *cobra.Command {
 				return err
 			}
 
-			d, err := c.Drops.Update(ctx, drop.UpdateBody{
+			body := drop.UpdateBody{
 				ID:    args.ID.Value,
 				Title: args.Title.Ptr(),
 				URL:   args.URL.Ptr(),
 				Tags:  args.Tags.Slice(),
+				Notes: args.Notes.Ptr(),
+			}
+			if cmd.Flags().Changed("tag") {
+				names := []string(args.TagNames)
+				body.TagNames = &names
+			}
+			d, err := c.Drops.Update(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(d)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func dropNotesCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Drop ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "notes",
+		Short:        "Edit a drop's notes in $EDITOR",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			d, err := c.Drops.Get(ctx, drop.GetParams{
+				ID: drop.ID(args.ID.Value),
 			})
 			if err != nil {
 				return err
 			}
 
+			notes, err := editText(d.Notes)
+			if err != nil {
+				return err
+			}
+			if notes != d.Notes {
+				d, err = c.Drops.Update(ctx, drop.UpdateBody{
+					ID:    args.ID.Value,
+					Notes: &notes,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
 			return json.NewEncoder(os.Stdout).Encode(d)
 		},
 	}
@@ -101,34 +325,137 @@ func dropEditCmd() *cobra.Command {
 	return cmd
 }
 
+// editText opens text in the user's editor and returns the edited text with
+// surrounding whitespace removed.
+func editText(text string) (string, error) {
+	f, err := os.CreateTemp("", "firehose-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if text != "" {
+		text += "\n"
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	// Use the shell so editors with arguments (like "code --wait") work.
+	c := exec.Command("sh", "-c", editor+` "$1"`, "sh", f.Name())
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("run editor: %w", err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
 func dropMoveCmd() *cobra.Command {
 	var args struct {
-		ID     null.UUID   `flag:"id,required" usage:"The Drop ID"`
+		IDs    moray.UUIDs `flag:"id" usage:"The Drop ID (repeatable, or read from stdin)"`
 		Status drop.Status `flag:"status" usage:"Set the status"`
 	}
 
 	cmd := &cobra.Command{
 		Use:          "move",
-		Short:        "Move a drop to a different status",
+		Short:        "Move drops to a different status",
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
 
+			ids, err := dropIDs(args.IDs)
+			if err != nil {
+				return err
+			}
+
 			c, err := Client()
 			if err != nil {
 				return err
 			}
 
-			d, err := c.Drops.Move(ctx, drop.MoveBody{
-				ID:     args.ID.Value,
-				Status: args.Status,
+			if len(ids) == 1 {
+				d, err := c.Drops.Move(ctx, drop.MoveBody{
+					ID:     ids[0],
+					Status: args.Status,
+				})
+				if err != nil {
+					return err
+				}
+				return json.NewEncoder(os.Stdout).Encode(d)
+			}
+
+			res, err := c.Drops.BulkMove(ctx, drop.BulkMoveBody{
+				Selection: drop.Selection{IDs: ids},
+				Status:    args.Status,
 			})
 			if err != nil {
 				return err
 			}
+			return encodeBulk(res)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
 
-			return json.NewEncoder(os.Stdout).Encode(d)
+func dropTagCmd() *cobra.Command {
+	var args struct {
+		IDs    moray.UUIDs   `flag:"id" usage:"The Drop ID (repeatable, or read from stdin)"`
+		Add    moray.Strings `flag:"add" usage:"Add this tag (repeatable)"`
+		Remove moray.Strings `flag:"remove" usage:"Remove this tag (repeatable)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "tag",
+		Short:        "Add or remove tags on drops",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			ids, err := dropIDs(args.IDs)
+			if err != nil {
+				return err
+			}
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			ts, err := c.Tags.List(ctx, tag.ListBody{})
+			if err != nil {
+				return err
+			}
+			body := drop.BulkTagBody{
+				Selection: drop.Selection{IDs: ids},
+			}
+			if body.AddTags, err = tagIDs(ts.Tags, args.Add); err != nil {
+				return err
+			}
+			if body.RemoveTags, err = tagIDs(ts.Tags, args.Remove); err != nil {
+				return err
+			}
+
+			res, err := c.Drops.BulkTag(ctx, body)
+			if err != nil {
+				return err
+			}
+			return encodeBulk(res)
 		},
 	}
 	moray.BindFlags(cmd, &args)
@@ -137,30 +464,44 @@ func dropMoveCmd() *cobra.Command {
 
 func dropDeleteCmd() *cobra.Command {
 	var args struct {
-		ID null.UUID `flag:"id,required" usage:"The Drop ID"`
+		IDs moray.UUIDs `flag:"id" usage:"The Drop ID (repeatable, or read from stdin)"`
 	}
 
 	cmd := &cobra.Command{
 		Use:          "delete",
-		Short:        "Delete a drop",
+		Short:        "Move drops to the trash",
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
 
+			ids, err := dropIDs(args.IDs)
+			if err != nil {
+				return err
+			}
+
 			c, err := Client()
 			if err != nil {
 				return err
 			}
 
-			d, err := c.Drops.Delete(ctx, drop.DeleteBody{
-				ID: args.ID.Value,
+			if len(ids) == 1 {
+				d, err := c.Drops.Delete(ctx, drop.DeleteBody{
+					ID: ids[0],
+				})
+				if err != nil {
+					return err
+				}
+				return json.NewEncoder(os.Stdout).Encode(d)
+			}
+
+			res, err := c.Drops.BulkDelete(ctx, drop.BulkDeleteBody{
+				Selection: drop.Selection{IDs: ids},
 			})
 			if err != nil {
 				return err
 			}
-
-			return json.NewEncoder(os.Stdout).Encode(d)
+			return encodeBulk(res)
 		},
 	}
 	moray.BindFlags(cmd, &args)
@@ -200,6 +541,18 @@ func dropGetCmd() *cobra.Command {
 }
 
 func dropNextCmd() *cobra.Command {
+	var args struct {
+		Tags            moray.Strings `flag:"tag" usage:"Only pick drops with this tag (repeatable)"`
+		ExcludeTags     moray.Strings `flag:"exclude-tag" usage:"Skip drops with this tag (repeatable)"`
+		Domains         moray.Strings `flag:"domain" usage:"Only pick drops on this domain (repeatable)"`
+		ExcludeDomains  moray.Strings `flag:"exclude-domain" usage:"Skip drops on this domain (repeatable)"`
+		Statuses        moray.Strings `flag:"status" usage:"Only pick drops with this status (repeatable, default: unread)"`
+		ExcludeStatuses moray.Strings `flag:"exclude-status" usage:"Skip drops with this status (repeatable)"`
+		Strategy        drop.Strategy `flag:"strategy" usage:"How to pick the drop: oldest, newest, random, or weighted (default: oldest)"`
+		MaxMinutes      null.Int32    `flag:"max-minutes" usage:"Only pick drops that take at most this many minutes to read"`
+		Query           null.String   `flag:"query" usage:"Only pick drops matching this query, like 'tag:work -domain:youtube.com'"`
+	}
+
 	cmd := &cobra.Command{
 		Use:          "next",
 		Short:        "Get the next unread drop",
@@ -213,7 +566,34 @@ func dropNextCmd() *cobra.Command {
 				return err
 			}
 
-			d, err := c.Drops.Next(ctx)
+			body := drop.NextBody{
+				Domains:        args.Domains,
+				ExcludeDomains: args.ExcludeDomains,
+				Strategy:       args.Strategy,
+
+				MaxReadingMinutes: args.MaxMinutes.Ptr(),
+				Query:             args.Query.Value,
+			}
+			if body.Statuses, err = parseStatuses(args.Statuses); err != nil {
+				return err
+			}
+			if body.ExcludeStatuses, err = parseStatuses(args.ExcludeStatuses); err != nil {
+				return err
+			}
+			if len(args.Tags) > 0 || len(args.ExcludeTags) > 0 {
+				ts, err := c.Tags.List(ctx, tag.ListBody{})
+				if err != nil {
+					return err
+				}
+				if body.Tags, err = tagIDs(ts.Tags, args.Tags); err != nil {
+					return err
+				}
+				if body.ExcludeTags, err = tagIDs(ts.Tags, args.ExcludeTags); err != nil {
+					return err
+				}
+			}
+
+			d, err := c.Drops.NextMatching(ctx, body)
 			if err != nil {
 				return err
 			}
@@ -221,14 +601,31 @@ func dropNextCmd() *cobra.Command {
 			return json.NewEncoder(os.Stdout).Encode(d)
 		},
 	}
+	moray.BindFlags(cmd, &args)
 	return cmd
 }
 
+func parseStatuses(names []string) ([]drop.Status, error) {
+	var ss []drop.Status
+	for _, n := range names {
+		s, err := drop.StatusString(n)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
 func dropListCmd() *cobra.Command {
 	var args struct {
 		Status drop.Status `flag:"status" usage:"The drop status"`
 		Limit  null.Int32  `flag:"limit" usage:"The maximum number of drops"`
 		Tags   moray.UUIDs `flag:"tags" usage:"List drops with these tags"`
+		Cursor drop.Cursor `flag:"cursor" usage:"Start after this cursor (from next_cursor)"`
+
+		MaxMinutes null.Int32  `flag:"max-minutes" usage:"Only list drops that take at most this many minutes to read"`
+		Query      null.String `flag:"query" usage:"Only list drops matching this query, like 'status:unread tag:work -domain:youtube.com'"`
 	}
 
 	cmd := &cobra.Command{
@@ -244,13 +641,87 @@ func dropListCmd() *cobra.Command {
 				return err
 			}
 
-			if args.Status == drop.StatusUnknown {
+			// The query can filter on status too, so only default to
+			// unread without one.
+			if args.Status == drop.StatusUnknown && args.Query.Value == "" {
 				args.Status = drop.StatusUnread
 			}
-			res, err := c.Drops.List(ctx, drop.ListBody{
+			body := drop.ListBody{
 				Status: args.Status,
 				Limit:  args.Limit.Ptr(),
 				Tags:   args.Tags.Slice(),
+
+				MaxReadingMinutes: args.MaxMinutes.Ptr(),
+				Query:             args.Query.Value,
+			}
+			if args.Cursor != (drop.Cursor{}) {
+				body.Cursor = &args.Cursor
+			}
+			res, err := c.Drops.List(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func dropSearchCmd() *cobra.Command {
+	var args struct {
+		Limit null.Int32 `flag:"limit" usage:"The maximum number of results"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "search QUERY",
+		Short:        "Search drop titles and URLs",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, argv []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Drops.Search(ctx, drop.SearchBody{
+				Query: argv[0],
+				Limit: args.Limit.Ptr(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func dropHistoryCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Drop ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "history",
+		Short:        "Show the changes made to a drop",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Drops.History(ctx, drop.HistoryBody{
+				ID: args.ID.Value,
 			})
 			if err != nil {
 				return err
@@ -262,3 +733,183 @@ func dropListCmd() *cobra.Command {
 	moray.BindFlags(cmd, &args)
 	return cmd
 }
+
+func dropReadCmd() *cobra.Command {
+	var args struct {
+		ID    null.UUID  `flag:"id,required" usage:"The Drop ID"`
+		Width null.Int32 `flag:"width" usage:"Wrap lines to this many columns (default $COLUMNS or 80)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "read",
+		Short:        "Print the saved text of a drop's page",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Drops.Content(ctx, drop.ContentBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+			if res.Error != "" {
+				return fmt.Errorf("could not read %s: %s", res.URL, res.Error)
+			}
+
+			width := 80
+			if args.Width.Present {
+				width = int(args.Width.Value)
+			} else if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+				width = n
+			}
+
+			text := res.Text
+			if res.Title != "" {
+				text = res.Title + "\n" + res.URL + "\n\n" + text
+			}
+			fmt.Println(clio.Wrap(text, width))
+			return nil
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func dropSnoozeCmd() *cobra.Command {
+	var args struct {
+		ID    null.UUID `flag:"id,required" usage:"The Drop ID"`
+		Until untilFlag `flag:"until,required" usage:"When to wake the drop: a duration (like 3d or 1w) or a date (like 2022-02-01)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "snooze",
+		Short:        "Hide a drop until later",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			d, err := c.Drops.Snooze(ctx, drop.SnoozeBody{
+				ID:    args.ID.Value,
+				Until: args.Until.Time,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(d)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func dropPinCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Drop ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "pin",
+		Short:        "Move an unread drop to the front of the queue",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			d, err := c.Drops.Pin(ctx, drop.PinBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(d)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func dropUnpinCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Drop ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "unpin",
+		Short:        "Put a pinned drop back in its place in the queue",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			d, err := c.Drops.Unpin(ctx, drop.UnpinBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(d)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+// untilFlag is a time in the future, given as either a duration from now or
+// an absolute time.
+type untilFlag struct {
+	time.Time
+}
+
+func (u *untilFlag) Set(s string) error {
+	if d, err := clock.ParseDuration(s); err == nil {
+		u.Time = time.Now().Add(d)
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		u.Time = t
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return fmt.Errorf("not a duration or date: %s", s)
+	}
+	u.Time = t
+	return nil
+}
+
+func (u *untilFlag) String() string {
+	if u.IsZero() {
+		return ""
+	}
+	return u.Format(time.RFC3339)
+}
+
+func (*untilFlag) Type() string {
+	return "until"
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+)
+
+func dropHighlightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "highlight",
+		Short: "Manage a drop's highlights",
+	}
+	cmd.AddCommand(
+		highlightAddCmd(),
+		highlightEditCmd(),
+		highlightRemoveCmd(),
+	)
+	return cmd
+}
+
+func highlightAddCmd() *cobra.Command {
+	var args struct {
+		DropID  null.UUID   `flag:"drop-id,required" usage:"The Drop ID"`
+		Quote   null.String `flag:"quote,required" usage:"The quoted text"`
+		Comment null.String `flag:"comment" usage:"A comment on the quote"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "add",
+		Short:        "Add a highlight to a drop",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			h, err := c.Drops.AddHighlight(ctx, drop.AddHighlightBody{
+				DropID:  args.DropID.Value,
+				Quote:   args.Quote.Value,
+				Comment: args.Comment.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(h)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func highlightEditCmd() *cobra.Command {
+	var args struct {
+		ID      null.UUID   `flag:"id,required" usage:"The Highlight ID"`
+		Quote   null.String `flag:"quote" usage:"Set the quoted text"`
+		Comment null.String `flag:"comment" usage:"Set the comment (empty to remove it)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "edit",
+		Short:        "Edit a highlight",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			h, err := c.Drops.EditHighlight(ctx, drop.EditHighlightBody{
+				ID:      args.ID.Value,
+				Quote:   args.Quote.Ptr(),
+				Comment: args.Comment.Ptr(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(h)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func highlightRemoveCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Highlight ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "remove",
+		Short:        "Remove a highlight",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			h, err := c.Drops.RemoveHighlight(ctx, drop.RemoveHighlightBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(h)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
@@ -62,6 +62,11 @@ func rootCmd() *cobra.Command {
 		wellknownCmd(),
 		authCmd(),
 		dropCmd(),
+		tagCmd(),
+		statsCmd(),
+		policyCmd(),
+		viewCmd(),
+		ruleCmd(),
 	)
 	return cmd
 }
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+	"github.com/metagram-net/firehose/policy"
+)
+
+func policyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Manage expiry policies",
+	}
+	cmd.AddCommand(
+		policyNewCmd(),
+		policyListCmd(),
+		policyEditCmd(),
+		policyDeleteCmd(),
+		policyPreviewCmd(),
+	)
+	return cmd
+}
+
+func policyNewCmd() *cobra.Command {
+	var args struct {
+		Name       null.String   `flag:"name,required" usage:"The policy name"`
+		Status     drop.Status   `flag:"status" usage:"The status of drops to act on"`
+		MaxAgeDays int32         `flag:"max-age-days,required" usage:"How many days drops can stay in the status"`
+		Action     policy.Action `flag:"action" usage:"What to do with old drops: expire or delete"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "new",
+		Short:        "Create a new expiry policy",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			p, err := c.Policies.Create(ctx, policy.CreateBody{
+				Name:       args.Name.Value,
+				Status:     args.Status,
+				MaxAgeDays: args.MaxAgeDays,
+				Action:     args.Action,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(p)
+		},
+	}
+	args.Status = drop.StatusUnread
+	args.Action = policy.ActionExpire
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func policyListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List expiry policies",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Policies.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	return cmd
+}
+
+func policyEditCmd() *cobra.Command {
+	var args struct {
+		ID         null.UUID     `flag:"id,required" usage:"The Policy ID"`
+		Name       null.String   `flag:"name" usage:"Set the name"`
+		Status     drop.Status   `flag:"status" usage:"Set the status of drops to act on"`
+		MaxAgeDays null.Int32    `flag:"max-age-days" usage:"Set how many days drops can stay in the status"`
+		Action     policy.Action `flag:"action" usage:"Set what to do with old drops: expire or delete"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "edit",
+		Short:        "Edit an expiry policy",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body := policy.UpdateBody{
+				ID:         args.ID.Value,
+				Name:       args.Name.Ptr(),
+				MaxAgeDays: args.MaxAgeDays.Ptr(),
+			}
+			if cmd.Flags().Changed("status") {
+				body.Status = &args.Status
+			}
+			if cmd.Flags().Changed("action") {
+				body.Action = &args.Action
+			}
+			p, err := c.Policies.Update(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(p)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func policyDeleteCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Policy ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "delete",
+		Short:        "Delete an expiry policy",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			p, err := c.Policies.Delete(ctx, policy.DeleteBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(p)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func policyPreviewCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id" usage:"The Policy ID (default: all policies)"`
+	}
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:          "preview",
+		Short:        "Show what expiry policies would do if they ran now",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			var body policy.PreviewBody
+			if args.ID.Present {
+				body.ID = &args.ID.Value
+			}
+			res, err := c.Policies.Preview(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				return json.NewEncoder(os.Stdout).Encode(res)
+			}
+			printPreviews(res.Previews)
+			return nil
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the raw JSON instead of tables")
+	return cmd
+}
+
+func printPreviews(ps []policy.Preview) {
+	if len(ps) == 0 {
+		fmt.Println("No expiry policies.")
+		return
+	}
+	for i, p := range ps {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s: %s drops %s for %d days (%s)\n", p.Policy.Name, p.Policy.Action, p.Policy.Status, p.Policy.MaxAgeDays, p.Policy.ID)
+		if p.Count == 0 {
+			fmt.Println("No drops to act on.")
+			continue
+		}
+		fmt.Printf("Would %s %d drops:\n", p.Policy.Action, p.Count)
+		t := newTable([]string{"ID", "Title", "Moved"})
+		for _, d := range p.Drops {
+			t.Append([]string{d.ID, d.Title, d.MovedAt.Format("2006-01-02")})
+		}
+		t.Render()
+		if n := p.Count - int64(len(p.Drops)); n > 0 {
+			fmt.Printf("...and %d more.\n", n)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+	"github.com/metagram-net/firehose/rule"
+)
+
+func ruleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rule",
+		Short: "Manage tag rules",
+	}
+	cmd.AddCommand(
+		ruleNewCmd(),
+		ruleListCmd(),
+		ruleEditCmd(),
+		ruleDeleteCmd(),
+		ruleApplyCmd(),
+	)
+	return cmd
+}
+
+func ruleNewCmd() *cobra.Command {
+	var args struct {
+		Match   rule.Match  `flag:"match" usage:"What to match: domain, url (a regular expression), or title (keywords)"`
+		Pattern null.String `flag:"pattern,required" usage:"The domain, URL pattern, or title keywords to match"`
+		Tag     null.String `flag:"tag,required" usage:"The tag name or ID to tag matching drops with"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "new",
+		Short:        "Create a new tag rule",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			id, err := tagID(ctx, c, args.Tag.Value)
+			if err != nil {
+				return err
+			}
+			r, err := c.Rules.Create(ctx, rule.CreateBody{
+				Match:   args.Match,
+				Pattern: args.Pattern.Value,
+				TagID:   id,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(r)
+		},
+	}
+	args.Match = rule.MatchDomain
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func ruleListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List tag rules",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Rules.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	return cmd
+}
+
+func ruleEditCmd() *cobra.Command {
+	var args struct {
+		ID      null.UUID   `flag:"id,required" usage:"The Rule ID"`
+		Match   rule.Match  `flag:"match" usage:"Set what to match: domain, url, or title"`
+		Pattern null.String `flag:"pattern" usage:"Set the domain, URL pattern, or title keywords to match"`
+		Tag     null.String `flag:"tag" usage:"Set the tag name or ID to tag matching drops with"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "edit",
+		Short:        "Edit a tag rule",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body := rule.UpdateBody{
+				ID:      args.ID.Value,
+				Pattern: args.Pattern.Ptr(),
+			}
+			if cmd.Flags().Changed("match") {
+				body.Match = &args.Match
+			}
+			if args.Tag.Present {
+				id, err := tagID(ctx, c, args.Tag.Value)
+				if err != nil {
+					return err
+				}
+				body.TagID = &id
+			}
+			r, err := c.Rules.Update(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(r)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func ruleDeleteCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Rule ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "delete",
+		Short:        "Delete a tag rule",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			r, err := c.Rules.Delete(ctx, rule.DeleteBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(r)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func ruleApplyCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id" usage:"The Rule ID (default: all rules)"`
+	}
+	var dryRun, asJSON bool
+
+	cmd := &cobra.Command{
+		Use:          "apply",
+		Short:        "Tag existing drops that match tag rules",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body := rule.ApplyBody{DryRun: dryRun}
+			if args.ID.Present {
+				body.ID = &args.ID.Value
+			}
+			res, err := c.Rules.Apply(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				return json.NewEncoder(os.Stdout).Encode(res)
+			}
+			printResults(res.Results, dryRun)
+			return nil
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show which drops would be tagged without tagging them")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the raw JSON instead of tables")
+	return cmd
+}
+
+func printResults(rs []rule.Result, dryRun bool) {
+	if len(rs) == 0 {
+		fmt.Println("No tag rules.")
+		return
+	}
+	verb := "Tagged"
+	if dryRun {
+		verb = "Would tag"
+	}
+	for i, r := range rs {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %q -> tag %s (%s)\n", r.Rule.Match, r.Rule.Pattern, r.Rule.TagID, r.Rule.ID)
+		if r.Count == 0 {
+			fmt.Println("No drops to tag.")
+			continue
+		}
+		fmt.Printf("%s %d drops:\n", verb, r.Count)
+		t := newTable([]string{"ID", "Title", "URL"})
+		for _, d := range r.Drops {
+			t.Append([]string{d.ID, d.Title, d.URL})
+		}
+		t.Render()
+		if n := r.Count - int64(len(r.Drops)); n > 0 {
+			fmt.Printf("...and %d more.\n", n)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/clio"
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+	"github.com/metagram-net/firehose/stats"
+)
+
+func statsCmd() *cobra.Command {
+	var args struct {
+		Interval stats.Interval `flag:"interval" usage:"The length of each period in the trend: day or week (default: day)"`
+		Periods  null.Int32     `flag:"periods" usage:"The number of periods in the trend"`
+	}
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:          "stats",
+		Short:        "Show how the queues are keeping up",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			s, err := c.Stats.Get(ctx, stats.Body{
+				Interval: args.Interval,
+				Periods:  args.Periods.Ptr(),
+			})
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				return json.NewEncoder(os.Stdout).Encode(s)
+			}
+			printStats(s)
+			return nil
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the raw JSON instead of tables")
+	return cmd
+}
+
+func printStats(s stats.Stats) {
+	t := newTable([]string{"Status", "Drops"})
+	t.AppendBulk([][]string{
+		{"unread", itoa(s.Counts.Unread)},
+		{"read", itoa(s.Counts.Read)},
+		{"saved", itoa(s.Counts.Saved)},
+		{"snoozed", itoa(s.Counts.Snoozed)},
+		{"expired", itoa(s.Counts.Expired)},
+	})
+	t.Render()
+
+	if s.OldestUnreadSeconds != nil {
+		age := time.Duration(*s.OldestUnreadSeconds) * time.Second
+		fmt.Printf("\nOldest unread drop: %s old\n", formatAge(age))
+	}
+
+	var created, consumed []int64
+	var totalCreated, totalConsumed int64
+	for _, p := range s.Trend {
+		created = append(created, p.Created)
+		consumed = append(consumed, p.Consumed)
+		totalCreated += p.Created
+		totalConsumed += p.Consumed
+	}
+	fmt.Printf("\nTrend over the last %d %ss:\n", len(s.Trend), s.Interval)
+	t = newTable([]string{"", "Trend", "Total"})
+	t.AppendBulk([][]string{
+		{"created", clio.Sparkline(created), itoa(totalCreated)},
+		{"consumed", clio.Sparkline(consumed), itoa(totalConsumed)},
+	})
+	t.Render()
+
+	if len(s.Tags) == 0 {
+		return
+	}
+	fmt.Println()
+	t = newTable([]string{"Tag", "Unread", "Read", "Saved", "Snoozed", "Expired"})
+	for _, tg := range s.Tags {
+		c := tg.Counts
+		t.Append([]string{tg.Name, itoa(c.Unread), itoa(c.Read), itoa(c.Saved), itoa(c.Snoozed), itoa(c.Expired)})
+	}
+	t.Render()
+}
+
+func newTable(header []string) *tablewriter.Table {
+	t := tablewriter.NewWriter(os.Stdout)
+	t.SetAutoFormatHeaders(false)
+	t.SetHeader(header)
+	return t
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// formatAge rounds an age to the largest whole unit, like "3d" or "5h".
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
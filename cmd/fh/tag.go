@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/client"
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func tagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage tags",
+	}
+	cmd.AddCommand(
+		tagNewCmd(),
+		tagListCmd(),
+		tagTreeCmd(),
+		tagGetCmd(),
+		tagRenameCmd(),
+		tagEditCmd(),
+		tagSetParentCmd(),
+		tagMergeCmd(),
+		tagDeleteCmd(),
+	)
+	return cmd
+}
+
+func tagNewCmd() *cobra.Command {
+	var args struct {
+		Name        null.String `flag:"name,required" usage:"The tag name"`
+		Parent      null.String `flag:"parent" usage:"The parent tag name or ID"`
+		Color       null.String `flag:"color" usage:"The tag color, like #1e90ff"`
+		Description null.String `flag:"description" usage:"What the tag is for"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "new",
+		Short:        "Create a new tag",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body := tag.CreateBody{
+				Name:        args.Name.Value,
+				Color:       args.Color.Value,
+				Description: args.Description.Value,
+			}
+			if args.Parent.Present {
+				id, err := tagID(ctx, c, args.Parent.Value)
+				if err != nil {
+					return err
+				}
+				body.ParentID = &id
+			}
+			t, err := c.Tags.Create(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(t)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func tagListCmd() *cobra.Command {
+	var args struct {
+		Sort tag.Sort `flag:"sort" usage:"How to sort the tags: name or usage"`
+	}
+	var unused bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List tags with how many drops have them",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Tags.List(ctx, tag.ListBody{
+				Sort:   args.Sort,
+				Unused: unused,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	args.Sort = tag.SortName
+	moray.BindFlags(cmd, &args)
+	cmd.Flags().BoolVar(&unused, "unused", false, "Only list tags that no drops have")
+	return cmd
+}
+
+func tagTreeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "tree",
+		Short:        "Print the tags as a tree",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Tags.List(ctx, tag.ListBody{})
+			if err != nil {
+				return err
+			}
+
+			if len(res.Tree) == 0 {
+				fmt.Println("No tags.")
+				return nil
+			}
+			printTree(res.Tree, 0)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printTree(nodes []tag.Node, depth int) {
+	for _, n := range nodes {
+		fmt.Printf("%s%s (%s)\n", strings.Repeat("  ", depth), n.Name, n.ID)
+		printTree(n.Children, depth+1)
+	}
+}
+
+func tagGetCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Tag ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "get",
+		Short:        "Get a tag",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			t, err := c.Tags.Get(ctx, tag.GetParams{
+				ID: tag.ID(args.ID.Value),
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(t)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func tagRenameCmd() *cobra.Command {
+	var args struct {
+		ID   null.UUID   `flag:"id,required" usage:"The Tag ID"`
+		Name null.String `flag:"name,required" usage:"The new tag name"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "rename",
+		Short:        "Rename a tag",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			t, err := c.Tags.Rename(ctx, tag.RenameBody{
+				ID:   args.ID.Value,
+				Name: args.Name.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(t)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func tagEditCmd() *cobra.Command {
+	var args struct {
+		ID          null.UUID   `flag:"id,required" usage:"The Tag ID"`
+		Color       null.String `flag:"color" usage:"Set the color, like #1e90ff (empty to clear)"`
+		Description null.String `flag:"description" usage:"Set what the tag is for (empty to clear)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "edit",
+		Short:        "Edit a tag's color and description",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			t, err := c.Tags.Update(ctx, tag.UpdateBody{
+				ID:          args.ID.Value,
+				Color:       args.Color.Ptr(),
+				Description: args.Description.Ptr(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(t)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func tagSetParentCmd() *cobra.Command {
+	var args struct {
+		ID     null.UUID   `flag:"id,required" usage:"The Tag ID"`
+		Parent null.String `flag:"parent" usage:"The new parent tag name or ID (default: move to the top level)"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "set-parent",
+		Short:        "Move a tag under another tag",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body := tag.SetParentBody{
+				ID: args.ID.Value,
+			}
+			if args.Parent.Present {
+				id, err := tagID(ctx, c, args.Parent.Value)
+				if err != nil {
+					return err
+				}
+				body.ParentID = &id
+			}
+			t, err := c.Tags.SetParent(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(t)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func tagMergeCmd() *cobra.Command {
+	var args struct {
+		Into null.String `flag:"into,required" usage:"The tag name or ID to merge into"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "merge --into TAG SOURCE...",
+		Short:        "Merge tags into another tag",
+		Long:         "Merge tags into another tag. Every drop with a source tag gets the target instead, and then the sources are deleted.",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, argv []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			target, err := tagID(ctx, c, args.Into.Value)
+			if err != nil {
+				return err
+			}
+			ts, err := c.Tags.List(ctx, tag.ListBody{})
+			if err != nil {
+				return err
+			}
+			ids, err := tagIDs(ts.Tags, argv)
+			if err != nil {
+				return err
+			}
+			// A source name can match the target too if the names are the
+			// same, but that's not a tag to merge.
+			var sources []uuid.UUID
+			for _, id := range ids {
+				if id != target {
+					sources = append(sources, id)
+				}
+			}
+
+			res, err := c.Tags.Merge(ctx, tag.MergeBody{
+				TargetID:  target,
+				SourceIDs: sources,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func tagDeleteCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Tag ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "delete",
+		Short:        "Delete a tag and remove it from all drops",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			t, err := c.Tags.Delete(ctx, tag.DeleteBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(t)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+var errUnknownTag = errors.New("unknown tag")
+
+// tagID looks up a single tag by name or ID.
+func tagID(ctx context.Context, c *client.Client, name string) (uuid.UUID, error) {
+	res, err := c.Tags.List(ctx, tag.ListBody{})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	ids, err := tagIDs(res.Tags, []string{name})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return ids[0], nil
+}
+
+// tagIDs looks up tags by name or ID.
+func tagIDs(tags []tag.Tag, names []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, n := range names {
+		found := false
+		for _, t := range tags {
+			if t.Name == n || t.ID == n {
+				ids = append(ids, uuid.FromStringOrNil(t.ID))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", errUnknownTag, n)
+		}
+	}
+	return ids, nil
+}
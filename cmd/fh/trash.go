@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+)
+
+func dropTrashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage deleted drops",
+	}
+	cmd.AddCommand(
+		trashListCmd(),
+		trashRestoreCmd(),
+		trashEmptyCmd(),
+	)
+	return cmd
+}
+
+func trashListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the drops in the trash",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Drops.Trash(ctx)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+}
+
+func trashRestoreCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The Drop ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "restore",
+		Short:        "Restore a drop from the trash",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			d, err := c.Drops.Restore(ctx, drop.RestoreBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(d)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func trashEmptyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "empty",
+		Short:        "Permanently delete all the drops in the trash",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Drops.EmptyTrash(ctx, drop.EmptyTrashBody{})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/metagram-net/firehose/client"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/moray"
+	"github.com/metagram-net/firehose/null"
+	"github.com/metagram-net/firehose/tag"
+	"github.com/metagram-net/firehose/view"
+)
+
+var errUnknownView = errors.New("unknown view")
+
+func viewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Manage saved views",
+	}
+	cmd.AddCommand(
+		viewNewCmd(),
+		viewListCmd(),
+		viewEditCmd(),
+		viewDeleteCmd(),
+		viewRunCmd(),
+	)
+	return cmd
+}
+
+// viewFilter has the flags for a view's filter, which are the same as for
+// fh drop list.
+type viewFilter struct {
+	Status     drop.Status   `flag:"status" usage:"Only list drops with this status"`
+	Limit      null.Int32    `flag:"limit" usage:"The maximum number of drops per page"`
+	Tags       moray.Strings `flag:"tag" usage:"Only list drops with this tag (repeatable)"`
+	MaxMinutes null.Int32    `flag:"max-minutes" usage:"Only list drops that take at most this many minutes to read"`
+	Query      null.String   `flag:"query" usage:"Only list drops matching this query, like 'status:unread tag:work'"`
+}
+
+var viewFilterFlags = []string{"status", "limit", "tag", "max-minutes", "query"}
+
+func (f viewFilter) body(ctx context.Context, c *client.Client) (drop.ListBody, error) {
+	body := drop.ListBody{
+		Status:            f.Status,
+		Limit:             f.Limit.Ptr(),
+		MaxReadingMinutes: f.MaxMinutes.Ptr(),
+		Query:             f.Query.Value,
+	}
+	if len(f.Tags) > 0 {
+		ts, err := c.Tags.List(ctx, tag.ListBody{})
+		if err != nil {
+			return drop.ListBody{}, err
+		}
+		ids, err := tagIDs(ts.Tags, f.Tags)
+		if err != nil {
+			return drop.ListBody{}, err
+		}
+		body.Tags = &ids
+	}
+	return body, nil
+}
+
+func viewNewCmd() *cobra.Command {
+	var args struct {
+		Name null.String `flag:"name,required" usage:"The view name"`
+	}
+	var filter viewFilter
+
+	cmd := &cobra.Command{
+		Use:          "new",
+		Short:        "Save a new view",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body, err := filter.body(ctx, c)
+			if err != nil {
+				return err
+			}
+			v, err := c.Views.Create(ctx, view.CreateBody{
+				Name:   args.Name.Value,
+				Filter: body,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(v)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	moray.BindFlags(cmd, &filter)
+	return cmd
+}
+
+func viewListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List saved views",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			res, err := c.Views.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	return cmd
+}
+
+func viewEditCmd() *cobra.Command {
+	var args struct {
+		ID   null.UUID   `flag:"id,required" usage:"The View ID"`
+		Name null.String `flag:"name" usage:"Set the name"`
+	}
+	var filter viewFilter
+
+	cmd := &cobra.Command{
+		Use:          "edit",
+		Short:        "Edit a saved view",
+		Long:         "Edit a saved view. Setting any of the filter flags replaces the whole filter.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			body := view.UpdateBody{
+				ID:   args.ID.Value,
+				Name: args.Name.Ptr(),
+			}
+			for _, f := range viewFilterFlags {
+				if cmd.Flags().Changed(f) {
+					b, err := filter.body(ctx, c)
+					if err != nil {
+						return err
+					}
+					body.Filter = &b
+					break
+				}
+			}
+			v, err := c.Views.Update(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(v)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	moray.BindFlags(cmd, &filter)
+	return cmd
+}
+
+func viewDeleteCmd() *cobra.Command {
+	var args struct {
+		ID null.UUID `flag:"id,required" usage:"The View ID"`
+	}
+
+	cmd := &cobra.Command{
+		Use:          "delete",
+		Short:        "Delete a saved view",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			v, err := c.Views.Delete(ctx, view.DeleteBody{
+				ID: args.ID.Value,
+			})
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(v)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	return cmd
+}
+
+func viewRunCmd() *cobra.Command {
+	var args struct {
+		Limit    null.Int32    `flag:"limit" usage:"The maximum number of drops (default: the view's limit)"`
+		Cursor   drop.Cursor   `flag:"cursor" usage:"Start after this cursor (from next_cursor)"`
+		Strategy drop.Strategy `flag:"strategy" usage:"With --next, how to pick the drop: oldest, newest, random, or weighted (default: oldest)"`
+	}
+	var next bool
+
+	cmd := &cobra.Command{
+		Use:          "run NAME",
+		Short:        "List the drops in a saved view",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, argv []string) error {
+			ctx := cmd.Context()
+
+			c, err := Client()
+			if err != nil {
+				return err
+			}
+
+			id, err := viewID(ctx, c, argv[0])
+			if err != nil {
+				return err
+			}
+
+			if next {
+				d, err := c.Views.NextDrop(ctx, view.NextDropBody{
+					ID:       id,
+					Strategy: args.Strategy,
+				})
+				if err != nil {
+					return err
+				}
+				return json.NewEncoder(os.Stdout).Encode(d)
+			}
+
+			body := view.ListDropsBody{
+				ID:    id,
+				Limit: args.Limit.Ptr(),
+			}
+			if args.Cursor != (drop.Cursor{}) {
+				body.Cursor = &args.Cursor
+			}
+			res, err := c.Views.ListDrops(ctx, body)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(res)
+		},
+	}
+	moray.BindFlags(cmd, &args)
+	cmd.Flags().BoolVar(&next, "next", false, "Get the next drop in the view instead of listing them")
+	return cmd
+}
+
+// viewID finds the view with the name (or ID).
+func viewID(ctx context.Context, c *client.Client, name string) (uuid.UUID, error) {
+	res, err := c.Views.List(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, v := range res.Views {
+		if v.Name == name || v.ID == name {
+			return uuid.FromStringOrNil(v.ID), nil
+		}
+	}
+	return uuid.Nil, fmt.Errorf("%w: %s", errUnknownView, name)
+}
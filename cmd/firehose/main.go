@@ -11,12 +11,16 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	_ "github.com/jackc/pgx/v4/stdlib" // database/sql driver: pgx
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/metagram-net/firehose/canonical"
+	"github.com/metagram-net/firehose/clock"
 	"github.com/metagram-net/firehose/server"
+	"github.com/metagram-net/firehose/worker"
 )
 
 func main() {
@@ -36,6 +40,7 @@ func initViper() error {
 
 	viper.SetDefault("host", "0.0.0.0")
 	viper.SetDefault("port", "3473")
+	viper.SetDefault("trash-retention", "30d")
 
 	err := viper.ReadInConfig()
 	var notFound viper.ConfigFileNotFoundError
@@ -52,11 +57,25 @@ func Main() error {
 		return err
 	}
 
+	var rules canonical.Rules
+	if err := viper.UnmarshalKey("canonical-url-rules", &rules); err != nil {
+		return err
+	}
+
+	retention, err := clock.ParseDuration(viper.GetString("trash-retention"))
+	if err != nil {
+		return fmt.Errorf("trash-retention: %w", err)
+	}
+
 	app, err := NewApp(Config{
 		DevelopmentLogger: viper.GetBool("development-logger"),
 		DatabaseURL:       viper.GetString("database-url"),
 		Host:              viper.GetString("host"),
 		Port:              viper.GetString("port"),
+
+		AllowPrivateAddresses: viper.GetBool("allow-private-addresses"),
+		CanonicalURLRules:     rules,
+		TrashRetention:        retention,
 	})
 	if err != nil {
 		return err
@@ -84,13 +103,29 @@ type Config struct {
 	DatabaseURL       string
 	Host              string
 	Port              string
+
+	// AllowPrivateAddresses lets background jobs fetch URLs on private
+	// networks. Only enable this for local development.
+	AllowPrivateAddresses bool
+
+	// CanonicalURLRules are extra rules for detecting duplicate drops, for
+	// sites with tracking parameters the defaults don't cover.
+	CanonicalURLRules canonical.Rules
+
+	// TrashRetention is how long deleted drops stay in the trash before
+	// they're purged.
+	TrashRetention time.Duration
 }
 
 type App struct {
-	log  *zap.Logger
-	db   *sql.DB
-	srv  *http.Server
-	done chan struct{}
+	log    *zap.Logger
+	db     *sql.DB
+	srv    *http.Server
+	worker *worker.Worker
+	done   chan struct{}
+
+	stopWorker chan struct{}
+	workerDone chan struct{}
 }
 
 func NewApp(cfg Config) (*App, error) {
@@ -105,17 +140,42 @@ func NewApp(cfg Config) (*App, error) {
 		return nil, err
 	}
 
+	srvCfg := server.Config{
+		CanonicalURLRules:     cfg.CanonicalURLRules,
+		AllowPrivateAddresses: cfg.AllowPrivateAddresses,
+		TrashRetention:        cfg.TrashRetention,
+	}
+
 	srv := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
-		Handler: server.New(log, db),
+		Handler: server.New(log, db, srvCfg),
 	}
 
-	done := make(chan struct{})
-
-	return &App{log, db, srv, done}, nil
+	wrk := server.NewWorker(log, db, srvCfg)
+
+	return &App{
+		log:        log,
+		db:         db,
+		srv:        srv,
+		worker:     wrk,
+		done:       make(chan struct{}),
+		stopWorker: make(chan struct{}),
+		workerDone: make(chan struct{}),
+	}, nil
 }
 
 func (a *App) Run() {
+	// Background jobs run until Shutdown says to stop.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-a.stopWorker
+		cancel()
+	}()
+	go func() {
+		defer close(a.workerDone)
+		a.worker.Run(ctx)
+	}()
+
 	a.log.Info("Listening", zap.String("address", a.srv.Addr))
 	if err := a.srv.ListenAndServe(); errors.Is(err, http.ErrServerClosed) {
 		a.log.Info("Clean shutdown. Bye! 👋")
@@ -136,6 +196,10 @@ func (a *App) Shutdown(ctx context.Context) error {
 		a.log.Error("Error shutting down HTTP server", zap.Error(err))
 	}
 
+	a.log.Info("Stopping background jobs")
+	close(a.stopWorker)
+	<-a.workerDone
+
 	a.log.Info("Closing database connection")
 	if err := a.db.Close(); err != nil {
 		a.log.Error("Error closing database connection", zap.Error(err))
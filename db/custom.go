@@ -8,6 +8,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/blockloop/scan"
 	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
 )
 
 var Pq = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
@@ -90,27 +91,59 @@ type DropUpdateSelect struct {
 }
 
 type DropUpdateSet struct {
-	Title *string
-	URL   *string
+	Title        *string
+	URL          *string
+	CanonicalURL *sql.NullString
+	Notes        *sql.NullString
 }
 
 type dropSelect struct {
-	ID        uuid.UUID      `db:"id"`
-	UserID    uuid.UUID      `db:"user_id"`
-	Title     sql.NullString `db:"title"`
-	URL       string         `db:"url"`
-	Status    DropStatus     `db:"status"`
-	MovedAt   time.Time      `db:"moved_at"`
-	CreatedAt time.Time      `db:"created_at"`
-	UpdatedAt time.Time      `db:"updated_at"`
+	ID                uuid.UUID      `db:"id"`
+	UserID            uuid.UUID      `db:"user_id"`
+	Title             sql.NullString `db:"title"`
+	URL               string         `db:"url"`
+	Status            DropStatus     `db:"status"`
+	MovedAt           time.Time      `db:"moved_at"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+	SearchVector      interface{}    `db:"search_vector"`
+	Description       sql.NullString `db:"description"`
+	SiteName          sql.NullString `db:"site_name"`
+	ImageURL          sql.NullString `db:"image_url"`
+	MetadataFetchedAt sql.NullTime   `db:"metadata_fetched_at"`
+	CanonicalURL      sql.NullString `db:"canonical_url"`
+	SnoozeUntil       sql.NullTime   `db:"snooze_until"`
+	Notes             sql.NullString `db:"notes"`
+	DeletedAt         sql.NullTime   `db:"deleted_at"`
+	WordCount         sql.NullInt32  `db:"word_count"`
+	ReadingMinutes    sql.NullInt32  `db:"reading_minutes"`
+	PinnedAt          sql.NullTime   `db:"pinned_at"`
+}
+
+// DropColumns lists the columns of the drops table, for use in custom select
+// statements that scan with ScanDrops.
+var DropColumns []string = mustColumns(scan.ColumnsStrict(new(dropSelect)))
+
+func ScanDrops(rows *sql.Rows) ([]Drop, error) {
+	var rs []dropSelect
+	if err := scan.RowsStrict(&rs, rows); err != nil {
+		return nil, err
+	}
+
+	ds := make([]Drop, 0, len(rs))
+	for _, d := range rs {
+		ds = append(ds, Drop(d))
+	}
+	return ds, nil
 }
 
 func DropUpdate(ctx context.Context, tx DBTX, f DropUpdateFields) (Drop, error) {
 	qq := Pq.
 		Update("drops").
 		Where(sq.Eq{
-			"id":      f.Select.ID,
-			"user_id": f.Select.UserID,
+			"id":         f.Select.ID,
+			"user_id":    f.Select.UserID,
+			"deleted_at": nil,
 		}).
 		Suffix("RETURNING *")
 
@@ -121,6 +154,12 @@ func DropUpdate(ctx context.Context, tx DBTX, f DropUpdateFields) (Drop, error)
 	if url := f.Set.URL; url != nil {
 		qq = qq.Set("url", url)
 	}
+	if url := f.Set.CanonicalURL; url != nil {
+		qq = qq.Set("canonical_url", *url)
+	}
+	if notes := f.Set.Notes; notes != nil {
+		qq = qq.Set("notes", *notes)
+	}
 
 	query, args, err := qq.ToSql()
 	if err != nil {
@@ -135,3 +174,114 @@ func DropUpdate(ctx context.Context, tx DBTX, f DropUpdateFields) (Drop, error)
 	var d dropSelect
 	return Drop(d), scan.RowStrict(&d, rows)
 }
+
+// A DropExportRow is a drop with the names of its tags and its highlights.
+type DropExportRow struct {
+	Drop
+	Tags       []string
+	Highlights []DropExportHighlight
+}
+
+type DropExportHighlight struct {
+	Quote     string
+	Comment   sql.NullString
+	CreatedAt time.Time
+}
+
+// DropsExport calls fn with each of the user's drops outside the trash, in
+// moved_at order. Rows are read one at a time, so the whole set never needs
+// to fit in memory.
+func DropsExport(ctx context.Context, tx DBTX, userID uuid.UUID, fn func(DropExportRow) error) error {
+	cols := make([]string, 0, len(DropColumns)+4)
+	for _, c := range DropColumns {
+		cols = append(cols, "drops."+c)
+	}
+	cols = append(cols,
+		"coalesce(array_agg(tags.name ORDER BY tags.name) FILTER (WHERE tags.id IS NOT NULL), '{}') AS tags",
+		"coalesce((SELECT array_agg(quote ORDER BY created_at, id) FROM drop_highlights WHERE drop_id = drops.id), '{}') AS highlight_quotes",
+		"coalesce((SELECT array_agg(comment ORDER BY created_at, id) FROM drop_highlights WHERE drop_id = drops.id), '{}') AS highlight_comments",
+		// Arrays of timestamps don't scan, so send microseconds since the epoch.
+		"coalesce((SELECT array_agg((extract(epoch FROM created_at) * 1000000)::bigint ORDER BY created_at, id) FROM drop_highlights WHERE drop_id = drops.id), '{}') AS highlight_created_ats",
+	)
+
+	query, args, err := Pq.
+		Select(cols...).
+		From("drops").
+		LeftJoin("drop_tags ON drop_tags.drop_id = drops.id").
+		LeftJoin("tags ON tags.id = drop_tags.tag_id").
+		Where(sq.Eq{
+			"drops.user_id":    userID,
+			"drops.deleted_at": nil,
+		}).
+		GroupBy("drops.id").
+		OrderBy("drops.moved_at asc", "drops.id asc").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			i        Drop
+			tags     []string
+			quotes   []string
+			comments []sql.NullString
+			times    []int64
+		)
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+			pq.Array(&tags),
+			pq.Array(&quotes),
+			pq.Array(&comments),
+			pq.Array(&times),
+		); err != nil {
+			return err
+		}
+
+		hs := make([]DropExportHighlight, 0, len(quotes))
+		for n, q := range quotes {
+			hs = append(hs, DropExportHighlight{
+				Quote:     q,
+				Comment:   comments[n],
+				CreatedAt: time.Unix(0, times[n]*int64(time.Microsecond)).UTC(),
+			})
+		}
+		err := fn(DropExportRow{
+			Drop:       i,
+			Tags:       tags,
+			Highlights: hs,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return rows.Err()
+}
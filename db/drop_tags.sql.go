@@ -34,9 +34,49 @@ func (q *Queries) DropTagApply(ctx context.Context, arg DropTagApplyParams) (Dro
 	return i, err
 }
 
+const dropTagsDetach = `-- name: DropTagsDetach :many
+delete from drop_tags
+using tags
+where drop_tags.tag_id = tags.id and tags.user_id = $1 and tags.id = $2
+returning drop_tags.id, drop_tags.drop_id, drop_tags.tag_id, drop_tags.created_at
+`
+
+type DropTagsDetachParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+func (q *Queries) DropTagsDetach(ctx context.Context, arg DropTagsDetachParams) ([]DropTag, error) {
+	rows, err := q.db.QueryContext(ctx, dropTagsDetach, arg.UserID, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DropTag
+	for rows.Next() {
+		var i DropTag
+		if err := rows.Scan(
+			&i.ID,
+			&i.DropID,
+			&i.TagID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const dropTagsIntersect = `-- name: DropTagsIntersect :many
 delete from drop_tags
-where drop_id = $1 and tag_id != any($2::uuid[])
+where drop_id = $1 and not (tag_id = any($2::uuid[]))
 returning id, drop_id, tag_id, created_at
 `
 
@@ -73,6 +113,56 @@ func (q *Queries) DropTagsIntersect(ctx context.Context, arg DropTagsIntersectPa
 	return items, nil
 }
 
+const dropTagsMergeDuplicates = `-- name: DropTagsMergeDuplicates :many
+delete from drop_tags
+where drop_tags.tag_id = any($1::uuid[])
+and exists (
+    select 1 from drop_tags other
+    where other.drop_id = drop_tags.drop_id
+    and (
+        other.tag_id = $2
+        or (other.tag_id = any($1::uuid[]) and other.id < drop_tags.id)
+    )
+)
+returning id, drop_id, tag_id, created_at
+`
+
+type DropTagsMergeDuplicatesParams struct {
+	SourceIds []uuid.UUID
+	TargetID  uuid.UUID
+}
+
+// Before merging, remove the source rows that would become duplicates: those
+// on drops that already have the target, and all but one of the sources on
+// each drop.
+func (q *Queries) DropTagsMergeDuplicates(ctx context.Context, arg DropTagsMergeDuplicatesParams) ([]DropTag, error) {
+	rows, err := q.db.QueryContext(ctx, dropTagsMergeDuplicates, pq.Array(arg.SourceIds), arg.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DropTag
+	for rows.Next() {
+		var i DropTag
+		if err := rows.Scan(
+			&i.ID,
+			&i.DropID,
+			&i.TagID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const dropTagsList = `-- name: DropTagsList :one
 select id, drop_id, tag_id, created_at from drop_tags
 where drop_id = $1
@@ -89,3 +179,81 @@ func (q *Queries) DropTagsList(ctx context.Context, dropID uuid.UUID) (DropTag,
 	)
 	return i, err
 }
+
+const dropTagsRemove = `-- name: DropTagsRemove :many
+delete from drop_tags
+where drop_id = $1 and tag_id = any($2::uuid[])
+returning id, drop_id, tag_id, created_at
+`
+
+type DropTagsRemoveParams struct {
+	DropID uuid.UUID
+	TagIds []uuid.UUID
+}
+
+func (q *Queries) DropTagsRemove(ctx context.Context, arg DropTagsRemoveParams) ([]DropTag, error) {
+	rows, err := q.db.QueryContext(ctx, dropTagsRemove, arg.DropID, pq.Array(arg.TagIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DropTag
+	for rows.Next() {
+		var i DropTag
+		if err := rows.Scan(
+			&i.ID,
+			&i.DropID,
+			&i.TagID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropTagsRepoint = `-- name: DropTagsRepoint :many
+update drop_tags set tag_id = $1
+where tag_id = any($2::uuid[])
+returning id, drop_id, tag_id, created_at
+`
+
+type DropTagsRepointParams struct {
+	TargetID  uuid.UUID
+	SourceIds []uuid.UUID
+}
+
+func (q *Queries) DropTagsRepoint(ctx context.Context, arg DropTagsRepointParams) ([]DropTag, error) {
+	rows, err := q.db.QueryContext(ctx, dropTagsRepoint, arg.TargetID, pq.Array(arg.SourceIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DropTag
+	for rows.Next() {
+		var i DropTag
+		if err := rows.Scan(
+			&i.ID,
+			&i.DropID,
+			&i.TagID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -14,17 +14,18 @@ import (
 
 const dropCreate = `-- name: DropCreate :one
 insert into drops
-(user_id, title, url, status, moved_at)
-values ($1, $2, $3, $4, $5)
-returning id, user_id, title, url, status, moved_at, created_at, updated_at
+(user_id, title, url, canonical_url, status, moved_at)
+values ($1, $2, $3, $4, $5, $6)
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
 `
 
 type DropCreateParams struct {
-	UserID  uuid.UUID
-	Title   sql.NullString
-	URL     string
-	Status  DropStatus
-	MovedAt time.Time
+	UserID       uuid.UUID
+	Title        sql.NullString
+	URL          string
+	CanonicalURL sql.NullString
+	Status       DropStatus
+	MovedAt      time.Time
 }
 
 func (q *Queries) DropCreate(ctx context.Context, arg DropCreateParams) (Drop, error) {
@@ -32,6 +33,7 @@ func (q *Queries) DropCreate(ctx context.Context, arg DropCreateParams) (Drop, e
 		arg.UserID,
 		arg.Title,
 		arg.URL,
+		arg.CanonicalURL,
 		arg.Status,
 		arg.MovedAt,
 	)
@@ -45,21 +47,34 @@ func (q *Queries) DropCreate(ctx context.Context, arg DropCreateParams) (Drop, e
 		&i.MovedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
 	)
 	return i, err
 }
 
-const dropDelete = `-- name: DropDelete :one
-delete from drops where user_id = $1 and id = $2 returning id, user_id, title, url, status, moved_at, created_at, updated_at
+const dropFind = `-- name: DropFind :one
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and id = $2 and deleted_at is null
 `
 
-type DropDeleteParams struct {
+type DropFindParams struct {
 	UserID uuid.UUID
 	ID     uuid.UUID
 }
 
-func (q *Queries) DropDelete(ctx context.Context, arg DropDeleteParams) (Drop, error) {
-	row := q.db.QueryRowContext(ctx, dropDelete, arg.UserID, arg.ID)
+func (q *Queries) DropFind(ctx context.Context, arg DropFindParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropFind, arg.UserID, arg.ID)
 	var i Drop
 	err := row.Scan(
 		&i.ID,
@@ -70,22 +85,72 @@ func (q *Queries) DropDelete(ctx context.Context, arg DropDeleteParams) (Drop, e
 		&i.MovedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
 	)
 	return i, err
 }
 
-const dropFind = `-- name: DropFind :one
-select id, user_id, title, url, status, moved_at, created_at, updated_at from drops
-where user_id = $1 and id = $2
+const dropFindByCanonicalURL = `-- name: DropFindByCanonicalURL :one
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and canonical_url = $2 and deleted_at is null
 `
 
-type DropFindParams struct {
+type DropFindByCanonicalURLParams struct {
+	UserID       uuid.UUID
+	CanonicalURL sql.NullString
+}
+
+func (q *Queries) DropFindByCanonicalURL(ctx context.Context, arg DropFindByCanonicalURLParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropFindByCanonicalURL, arg.UserID, arg.CanonicalURL)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropFindTrashed = `-- name: DropFindTrashed :one
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and id = $2 and deleted_at is not null
+`
+
+type DropFindTrashedParams struct {
 	UserID uuid.UUID
 	ID     uuid.UUID
 }
 
-func (q *Queries) DropFind(ctx context.Context, arg DropFindParams) (Drop, error) {
-	row := q.db.QueryRowContext(ctx, dropFind, arg.UserID, arg.ID)
+func (q *Queries) DropFindTrashed(ctx context.Context, arg DropFindTrashedParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropFindTrashed, arg.UserID, arg.ID)
 	var i Drop
 	err := row.Scan(
 		&i.ID,
@@ -96,25 +161,115 @@ func (q *Queries) DropFind(ctx context.Context, arg DropFindParams) (Drop, error
 		&i.MovedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropImport = `-- name: DropImport :one
+insert into drops
+(user_id, title, url, canonical_url, status, moved_at, created_at, snooze_until, pinned_at)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropImportParams struct {
+	UserID       uuid.UUID
+	Title        sql.NullString
+	URL          string
+	CanonicalURL sql.NullString
+	Status       DropStatus
+	MovedAt      time.Time
+	CreatedAt    time.Time
+	SnoozeUntil  sql.NullTime
+	PinnedAt     sql.NullTime
+}
+
+// Unlike DropCreate, imported drops keep their own created_at and pinned_at,
+// and a snooze_until separate from moved_at.
+func (q *Queries) DropImport(ctx context.Context, arg DropImportParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropImport,
+		arg.UserID,
+		arg.Title,
+		arg.URL,
+		arg.CanonicalURL,
+		arg.Status,
+		arg.MovedAt,
+		arg.CreatedAt,
+		arg.SnoozeUntil,
+		arg.PinnedAt,
+	)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
 	)
 	return i, err
 }
 
 const dropList = `-- name: DropList :many
-select id, user_id, title, url, status, moved_at, created_at, updated_at from drops
-where user_id = $1 and status = ANY($3::drop_status[])
-order by moved_at asc
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and deleted_at is null and status = ANY($3::drop_status[])
+and (status != 'snoozed' or (snooze_until <= $4::timestamp) = $5::bool)
+and (pinned_at is null, coalesce(pinned_at, '0001-01-01'), moved_at, id)
+    > ($6::bool, $7::timestamp, $8::timestamp, $9::uuid)
+order by pinned_at asc nulls last, moved_at asc, id asc
 limit $2
 `
 
 type DropListParams struct {
-	UserID   uuid.UUID
-	Limit    int32
-	Statuses []DropStatus
+	UserID        uuid.UUID
+	Limit         int32
+	Statuses      []DropStatus
+	Now           time.Time
+	Awake         bool
+	AfterUnpinned bool
+	AfterPinnedAt time.Time
+	AfterMovedAt  time.Time
+	AfterID       uuid.UUID
 }
 
 func (q *Queries) DropList(ctx context.Context, arg DropListParams) ([]Drop, error) {
-	rows, err := q.db.QueryContext(ctx, dropList, arg.UserID, arg.Limit, pq.Array(arg.Statuses))
+	rows, err := q.db.QueryContext(ctx, dropList,
+		arg.UserID,
+		arg.Limit,
+		pq.Array(arg.Statuses),
+		arg.Now,
+		arg.Awake,
+		arg.AfterUnpinned,
+		arg.AfterPinnedAt,
+		arg.AfterMovedAt,
+		arg.AfterID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +286,18 @@ func (q *Queries) DropList(ctx context.Context, arg DropListParams) ([]Drop, err
 			&i.MovedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -145,11 +312,47 @@ func (q *Queries) DropList(ctx context.Context, arg DropListParams) ([]Drop, err
 	return items, nil
 }
 
+const dropMetadataPending = `-- name: DropMetadataPending :one
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where metadata_fetched_at is null and coalesce(title, '') = '' and deleted_at is null
+order by created_at asc
+limit 1
+for update skip locked
+`
+
+func (q *Queries) DropMetadataPending(ctx context.Context) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropMetadataPending)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
 const dropMove = `-- name: DropMove :one
 update drops
-set status = $3, moved_at = $4
-where user_id = $1 and id = $2
-returning id, user_id, title, url, status, moved_at, created_at, updated_at
+set status = $3, moved_at = $4, snooze_until = null, pinned_at = null
+where user_id = $1 and id = $2 and deleted_at is null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
 `
 
 type DropMoveParams struct {
@@ -176,18 +379,424 @@ func (q *Queries) DropMove(ctx context.Context, arg DropMoveParams) (Drop, error
 		&i.MovedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
 	)
 	return i, err
 }
 
 const dropNext = `-- name: DropNext :one
-select id, user_id, title, url, status, moved_at, created_at, updated_at from drops
-where user_id = $1 and status = 'unread'
-order by moved_at asc
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and deleted_at is null
+and (status = 'unread' or (status = 'snoozed' and snooze_until <= $2::timestamp))
+order by pinned_at asc nulls last, moved_at asc
+`
+
+type DropNextParams struct {
+	UserID uuid.UUID
+	Now    time.Time
+}
+
+func (q *Queries) DropNext(ctx context.Context, arg DropNextParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropNext, arg.UserID, arg.Now)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropPin = `-- name: DropPin :one
+update drops
+set status = 'unread', snooze_until = null, pinned_at = coalesce(pinned_at, $3::timestamp)
+where user_id = $1 and id = $2 and deleted_at is null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropPinParams struct {
+	UserID   uuid.UUID
+	ID       uuid.UUID
+	PinnedAt time.Time
+}
+
+func (q *Queries) DropPin(ctx context.Context, arg DropPinParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropPin, arg.UserID, arg.ID, arg.PinnedAt)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropRestore = `-- name: DropRestore :one
+update drops set deleted_at = null
+where user_id = $1 and id = $2 and deleted_at is not null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropRestoreParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+func (q *Queries) DropRestore(ctx context.Context, arg DropRestoreParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropRestore, arg.UserID, arg.ID)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropSearch = `-- name: DropSearch :many
+select drops.id, drops.user_id, drops.title, drops.url, drops.status, drops.moved_at, drops.created_at, drops.updated_at, drops.search_vector, drops.description, drops.site_name, drops.image_url, drops.metadata_fetched_at, drops.canonical_url, drops.snooze_until, drops.notes, drops.deleted_at, drops.word_count, drops.reading_minutes, drops.pinned_at,
+    ts_rank(drops.search_vector || coalesce(drop_contents.search_vector, ''::tsvector), query)::real as rank,
+    ts_headline('english', coalesce(drops.title, '') || ' ' || drops.url || ' ' || coalesce(drop_contents.text, ''), query)::text as snippet
+from drops
+cross join websearch_to_tsquery('english', $1::text) query
+left join drop_contents on drop_contents.drop_id = drops.id
+where drops.user_id = $2 and drops.deleted_at is null
+and (drops.search_vector @@ query or drop_contents.search_vector @@ query)
+order by rank desc, drops.moved_at asc
+limit $3
+`
+
+type DropSearchParams struct {
+	Query      string
+	UserID     uuid.UUID
+	MaxResults int32
+}
+
+type DropSearchRow struct {
+	ID                uuid.UUID
+	UserID            uuid.UUID
+	Title             sql.NullString
+	URL               string
+	Status            DropStatus
+	MovedAt           time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	SearchVector      interface{}
+	Description       sql.NullString
+	SiteName          sql.NullString
+	ImageURL          sql.NullString
+	MetadataFetchedAt sql.NullTime
+	CanonicalURL      sql.NullString
+	SnoozeUntil       sql.NullTime
+	Notes             sql.NullString
+	DeletedAt         sql.NullTime
+	WordCount         sql.NullInt32
+	ReadingMinutes    sql.NullInt32
+	PinnedAt          sql.NullTime
+	Rank              float32
+	Snippet           string
+}
+
+// Matches on the fetched article text too, if there is any.
+func (q *Queries) DropSearch(ctx context.Context, arg DropSearchParams) ([]DropSearchRow, error) {
+	rows, err := q.db.QueryContext(ctx, dropSearch, arg.Query, arg.UserID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DropSearchRow
+	for rows.Next() {
+		var i DropSearchRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropSetMetadata = `-- name: DropSetMetadata :one
+update drops
+set title = coalesce(nullif(title, ''), nullif($1::text, '')),
+    description = $2,
+    site_name = $3,
+    image_url = $4,
+    metadata_fetched_at = $5::timestamp
+where id = $6
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropSetMetadataParams struct {
+	Title       string
+	Description sql.NullString
+	SiteName    sql.NullString
+	ImageURL    sql.NullString
+	FetchedAt   time.Time
+	ID          uuid.UUID
+}
+
+func (q *Queries) DropSetMetadata(ctx context.Context, arg DropSetMetadataParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropSetMetadata,
+		arg.Title,
+		arg.Description,
+		arg.SiteName,
+		arg.ImageURL,
+		arg.FetchedAt,
+		arg.ID,
+	)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropSetReadingTime = `-- name: DropSetReadingTime :one
+update drops
+set word_count = $1,
+    reading_minutes = $2
+where id = $3
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropSetReadingTimeParams struct {
+	WordCount      sql.NullInt32
+	ReadingMinutes sql.NullInt32
+	ID             uuid.UUID
+}
+
+func (q *Queries) DropSetReadingTime(ctx context.Context, arg DropSetReadingTimeParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropSetReadingTime, arg.WordCount, arg.ReadingMinutes, arg.ID)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropSnooze = `-- name: DropSnooze :one
+update drops
+set status = 'snoozed', moved_at = $3::timestamp, snooze_until = $3::timestamp, pinned_at = null
+where user_id = $1 and id = $2 and deleted_at is null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropSnoozeParams struct {
+	UserID      uuid.UUID
+	ID          uuid.UUID
+	SnoozeUntil time.Time
+}
+
+func (q *Queries) DropSnooze(ctx context.Context, arg DropSnoozeParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropSnooze, arg.UserID, arg.ID, arg.SnoozeUntil)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropTrash = `-- name: DropTrash :one
+update drops set deleted_at = $3::timestamp
+where user_id = $1 and id = $2 and deleted_at is null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+type DropTrashParams struct {
+	UserID    uuid.UUID
+	ID        uuid.UUID
+	DeletedAt time.Time
+}
+
+func (q *Queries) DropTrash(ctx context.Context, arg DropTrashParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropTrash, arg.UserID, arg.ID, arg.DeletedAt)
+	var i Drop
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.URL,
+		&i.Status,
+		&i.MovedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const dropUnpin = `-- name: DropUnpin :one
+update drops set pinned_at = null
+where user_id = $1 and id = $2 and deleted_at is null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
 `
 
-func (q *Queries) DropNext(ctx context.Context, userID uuid.UUID) (Drop, error) {
-	row := q.db.QueryRowContext(ctx, dropNext, userID)
+type DropUnpinParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+func (q *Queries) DropUnpin(ctx context.Context, arg DropUnpinParams) (Drop, error) {
+	row := q.db.QueryRowContext(ctx, dropUnpin, arg.UserID, arg.ID)
 	var i Drop
 	err := row.Scan(
 		&i.ID,
@@ -198,6 +807,392 @@ func (q *Queries) DropNext(ctx context.Context, userID uuid.UUID) (Drop, error)
 		&i.MovedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Description,
+		&i.SiteName,
+		&i.ImageURL,
+		&i.MetadataFetchedAt,
+		&i.CanonicalURL,
+		&i.SnoozeUntil,
+		&i.Notes,
+		&i.DeletedAt,
+		&i.WordCount,
+		&i.ReadingMinutes,
+		&i.PinnedAt,
 	)
 	return i, err
 }
+
+const dropsEmptyTrash = `-- name: DropsEmptyTrash :many
+delete from drops
+where user_id = $1 and deleted_at is not null
+returning id
+`
+
+func (q *Queries) DropsEmptyTrash(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, dropsEmptyTrash, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropsExpirable = `-- name: DropsExpirable :many
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and status = $2 and moved_at <= $3::timestamp and deleted_at is null
+order by moved_at asc, id asc
+limit $4
+for update skip locked
+`
+
+type DropsExpirableParams struct {
+	UserID      uuid.UUID
+	Status      DropStatus
+	MovedBefore time.Time
+	MaxDrops    int32
+}
+
+func (q *Queries) DropsExpirable(ctx context.Context, arg DropsExpirableParams) ([]Drop, error) {
+	rows, err := q.db.QueryContext(ctx, dropsExpirable,
+		arg.UserID,
+		arg.Status,
+		arg.MovedBefore,
+		arg.MaxDrops,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Drop
+	for rows.Next() {
+		var i Drop
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropsExpiring = `-- name: DropsExpiring :many
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and status = $2 and moved_at <= $3::timestamp and deleted_at is null
+order by moved_at asc, id asc
+limit $4
+`
+
+type DropsExpiringParams struct {
+	UserID      uuid.UUID
+	Status      DropStatus
+	MovedBefore time.Time
+	MaxDrops    int32
+}
+
+func (q *Queries) DropsExpiring(ctx context.Context, arg DropsExpiringParams) ([]Drop, error) {
+	rows, err := q.db.QueryContext(ctx, dropsExpiring,
+		arg.UserID,
+		arg.Status,
+		arg.MovedBefore,
+		arg.MaxDrops,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Drop
+	for rows.Next() {
+		var i Drop
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropsExpiringCount = `-- name: DropsExpiringCount :one
+select count(*) from drops
+where user_id = $1 and status = $2 and moved_at <= $3::timestamp and deleted_at is null
+`
+
+type DropsExpiringCountParams struct {
+	UserID      uuid.UUID
+	Status      DropStatus
+	MovedBefore time.Time
+}
+
+func (q *Queries) DropsExpiringCount(ctx context.Context, arg DropsExpiringCountParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, dropsExpiringCount, arg.UserID, arg.Status, arg.MovedBefore)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const dropsPurge = `-- name: DropsPurge :many
+delete from drops
+where id in (
+    select id from drops
+    where deleted_at <= $1::timestamp
+    order by deleted_at asc
+    limit $2
+    for update skip locked
+)
+returning id
+`
+
+type DropsPurgeParams struct {
+	TrashedBefore time.Time
+	MaxDrops      int32
+}
+
+func (q *Queries) DropsPurge(ctx context.Context, arg DropsPurgeParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, dropsPurge, arg.TrashedBefore, arg.MaxDrops)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropsTagged = `-- name: DropsTagged :many
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and exists (
+    select 1 from drop_tags
+    where drop_tags.drop_id = drops.id and drop_tags.tag_id = any($2::uuid[])
+)
+order by id
+`
+
+type DropsTaggedParams struct {
+	UserID uuid.UUID
+	TagIds []uuid.UUID
+}
+
+// Includes trashed drops, since tag changes apply to them too.
+func (q *Queries) DropsTagged(ctx context.Context, arg DropsTaggedParams) ([]Drop, error) {
+	rows, err := q.db.QueryContext(ctx, dropsTagged, arg.UserID, pq.Array(arg.TagIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Drop
+	for rows.Next() {
+		var i Drop
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropsTrashed = `-- name: DropsTrashed :many
+select id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at from drops
+where user_id = $1 and deleted_at is not null
+order by deleted_at desc, id desc
+`
+
+func (q *Queries) DropsTrashed(ctx context.Context, userID uuid.UUID) ([]Drop, error) {
+	rows, err := q.db.QueryContext(ctx, dropsTrashed, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Drop
+	for rows.Next() {
+		var i Drop
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dropsWake = `-- name: DropsWake :many
+update drops
+set status = 'unread', snooze_until = null
+where status = 'snoozed' and snooze_until <= $1::timestamp and deleted_at is null
+returning id, user_id, title, url, status, moved_at, created_at, updated_at, search_vector, description, site_name, image_url, metadata_fetched_at, canonical_url, snooze_until, notes, deleted_at, word_count, reading_minutes, pinned_at
+`
+
+func (q *Queries) DropsWake(ctx context.Context, now time.Time) ([]Drop, error) {
+	rows, err := q.db.QueryContext(ctx, dropsWake, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Drop
+	for rows.Next() {
+		var i Drop
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.URL,
+			&i.Status,
+			&i.MovedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Description,
+			&i.SiteName,
+			&i.ImageURL,
+			&i.MetadataFetchedAt,
+			&i.CanonicalURL,
+			&i.SnoozeUntil,
+			&i.Notes,
+			&i.DeletedAt,
+			&i.WordCount,
+			&i.ReadingMinutes,
+			&i.PinnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
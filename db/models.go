@@ -4,18 +4,44 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/gofrs/uuid"
 )
 
+type DropEventKind string
+
+const (
+	DropEventKindCreate  DropEventKind = "create"
+	DropEventKindUpdate  DropEventKind = "update"
+	DropEventKindTag     DropEventKind = "tag"
+	DropEventKindMove    DropEventKind = "move"
+	DropEventKindDelete  DropEventKind = "delete"
+	DropEventKindRestore DropEventKind = "restore"
+)
+
+func (e *DropEventKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DropEventKind(s)
+	case string:
+		*e = DropEventKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DropEventKind: %T", src)
+	}
+	return nil
+}
+
 type DropStatus string
 
 const (
-	DropStatusUnread DropStatus = "unread"
-	DropStatusRead   DropStatus = "read"
-	DropStatusSaved  DropStatus = "saved"
+	DropStatusUnread  DropStatus = "unread"
+	DropStatusRead    DropStatus = "read"
+	DropStatusSaved   DropStatus = "saved"
+	DropStatusSnoozed DropStatus = "snoozed"
+	DropStatusExpired DropStatus = "expired"
 )
 
 func (e *DropStatus) Scan(src interface{}) error {
@@ -30,6 +56,45 @@ func (e *DropStatus) Scan(src interface{}) error {
 	return nil
 }
 
+type ExpiryAction string
+
+const (
+	ExpiryActionExpire ExpiryAction = "expire"
+	ExpiryActionDelete ExpiryAction = "delete"
+)
+
+func (e *ExpiryAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ExpiryAction(s)
+	case string:
+		*e = ExpiryAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ExpiryAction: %T", src)
+	}
+	return nil
+}
+
+type TagRuleMatch string
+
+const (
+	TagRuleMatchDomain TagRuleMatch = "domain"
+	TagRuleMatchUrl    TagRuleMatch = "url"
+	TagRuleMatchTitle  TagRuleMatch = "title"
+)
+
+func (e *TagRuleMatch) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TagRuleMatch(s)
+	case string:
+		*e = TagRuleMatch(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TagRuleMatch: %T", src)
+	}
+	return nil
+}
+
 type ApiKey struct {
 	ID           uuid.UUID
 	UserID       uuid.UUID
@@ -40,12 +105,57 @@ type ApiKey struct {
 }
 
 type Drop struct {
+	ID                uuid.UUID
+	UserID            uuid.UUID
+	Title             sql.NullString
+	URL               string
+	Status            DropStatus
+	MovedAt           time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	SearchVector      interface{}
+	Description       sql.NullString
+	SiteName          sql.NullString
+	ImageURL          sql.NullString
+	MetadataFetchedAt sql.NullTime
+	CanonicalURL      sql.NullString
+	SnoozeUntil       sql.NullTime
+	Notes             sql.NullString
+	DeletedAt         sql.NullTime
+	WordCount         sql.NullInt32
+	ReadingMinutes    sql.NullInt32
+	PinnedAt          sql.NullTime
+}
+
+type DropContent struct {
+	DropID       uuid.UUID
+	UserID       uuid.UUID
+	URL          string
+	Title        string
+	Text         string
+	HTML         string
+	Error        sql.NullString
+	FetchedAt    time.Time
+	SearchVector interface{}
+}
+
+type DropEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	DropID    uuid.UUID
+	Kind      DropEventKind
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+	PolicyID  uuid.NullUUID
+}
+
+type DropHighlight struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
-	Title     sql.NullString
-	URL       string
-	Status    DropStatus
-	MovedAt   time.Time
+	DropID    uuid.UUID
+	Quote     string
+	Comment   sql.NullString
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -57,6 +167,17 @@ type DropTag struct {
 	CreatedAt time.Time
 }
 
+type ExpiryPolicy struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Status     DropStatus
+	MaxAgeDays int32
+	Action     ExpiryAction
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
 type SchemaMigration struct {
 	ID    int32
 	Slug  string
@@ -64,9 +185,22 @@ type SchemaMigration struct {
 }
 
 type Tag struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ParentID    uuid.NullUUID
+	Color       sql.NullString
+	Description sql.NullString
+}
+
+type TagRule struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
-	Name      string
+	Match     TagRuleMatch
+	Pattern   string
+	TagID     uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -77,3 +211,12 @@ type User struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
+
+type View struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Filter    json.RawMessage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"time"
 )
 
 func NullString(s *string) sql.NullString {
@@ -13,3 +14,13 @@ func NullString(s *string) sql.NullString {
 		Valid:  true,
 	}
 }
+
+func NullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{
+		Time:  *t,
+		Valid: true,
+	}
+}
@@ -4,6 +4,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
@@ -11,25 +12,83 @@ import (
 type Querier interface {
 	ApiKeyCreate(ctx context.Context, arg ApiKeyCreateParams) (ApiKey, error)
 	ApiKeyFind(ctx context.Context, arg ApiKeyFindParams) (ApiKey, error)
+	DropContentFind(ctx context.Context, arg DropContentFindParams) (DropContent, error)
+	DropContentPending(ctx context.Context) (Drop, error)
+	DropContentSave(ctx context.Context, arg DropContentSaveParams) (DropContent, error)
+	DropContentsDelete(ctx context.Context, arg DropContentsDeleteParams) ([]DropContent, error)
 	DropCreate(ctx context.Context, arg DropCreateParams) (Drop, error)
-	DropDelete(ctx context.Context, arg DropDeleteParams) (Drop, error)
+	DropEventCreate(ctx context.Context, arg DropEventCreateParams) (DropEvent, error)
+	DropEventList(ctx context.Context, arg DropEventListParams) ([]DropEvent, error)
 	DropFind(ctx context.Context, arg DropFindParams) (Drop, error)
+	DropFindByCanonicalURL(ctx context.Context, arg DropFindByCanonicalURLParams) (Drop, error)
+	DropFindTrashed(ctx context.Context, arg DropFindTrashedParams) (Drop, error)
+	DropHighlightCreate(ctx context.Context, arg DropHighlightCreateParams) (DropHighlight, error)
+	DropHighlightDelete(ctx context.Context, arg DropHighlightDeleteParams) (DropHighlight, error)
+	DropHighlightFind(ctx context.Context, arg DropHighlightFindParams) (DropHighlight, error)
+	DropHighlightImport(ctx context.Context, arg DropHighlightImportParams) (DropHighlight, error)
+	DropHighlightUpdate(ctx context.Context, arg DropHighlightUpdateParams) (DropHighlight, error)
+	DropHighlightsList(ctx context.Context, arg DropHighlightsListParams) ([]DropHighlight, error)
+	DropImport(ctx context.Context, arg DropImportParams) (Drop, error)
 	DropList(ctx context.Context, arg DropListParams) ([]Drop, error)
+	DropMetadataPending(ctx context.Context) (Drop, error)
 	DropMove(ctx context.Context, arg DropMoveParams) (Drop, error)
-	DropNext(ctx context.Context, userID uuid.UUID) (Drop, error)
+	DropNext(ctx context.Context, arg DropNextParams) (Drop, error)
+	DropPin(ctx context.Context, arg DropPinParams) (Drop, error)
+	DropRestore(ctx context.Context, arg DropRestoreParams) (Drop, error)
+	DropSearch(ctx context.Context, arg DropSearchParams) ([]DropSearchRow, error)
+	DropSetMetadata(ctx context.Context, arg DropSetMetadataParams) (Drop, error)
+	DropSetReadingTime(ctx context.Context, arg DropSetReadingTimeParams) (Drop, error)
+	DropSnooze(ctx context.Context, arg DropSnoozeParams) (Drop, error)
 	DropTagApply(ctx context.Context, arg DropTagApplyParams) (DropTag, error)
+	DropTagsDetach(ctx context.Context, arg DropTagsDetachParams) ([]DropTag, error)
 	DropTagsIntersect(ctx context.Context, arg DropTagsIntersectParams) ([]DropTag, error)
+	DropTagsMergeDuplicates(ctx context.Context, arg DropTagsMergeDuplicatesParams) ([]DropTag, error)
 	DropTagsList(ctx context.Context, dropID uuid.UUID) (DropTag, error)
+	DropTagsRemove(ctx context.Context, arg DropTagsRemoveParams) ([]DropTag, error)
+	DropTagsRepoint(ctx context.Context, arg DropTagsRepointParams) ([]DropTag, error)
+	DropTrash(ctx context.Context, arg DropTrashParams) (Drop, error)
+	DropUnpin(ctx context.Context, arg DropUnpinParams) (Drop, error)
+	DropsEmptyTrash(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	DropsExpirable(ctx context.Context, arg DropsExpirableParams) ([]Drop, error)
+	DropsExpiring(ctx context.Context, arg DropsExpiringParams) ([]Drop, error)
+	DropsExpiringCount(ctx context.Context, arg DropsExpiringCountParams) (int64, error)
+	DropsPurge(ctx context.Context, arg DropsPurgeParams) ([]uuid.UUID, error)
+	DropsTagged(ctx context.Context, arg DropsTaggedParams) ([]Drop, error)
+	DropsTrashed(ctx context.Context, userID uuid.UUID) ([]Drop, error)
+	DropsWake(ctx context.Context, now time.Time) ([]Drop, error)
+	ExpiryPoliciesAll(ctx context.Context) ([]ExpiryPolicy, error)
+	ExpiryPolicyCreate(ctx context.Context, arg ExpiryPolicyCreateParams) (ExpiryPolicy, error)
+	ExpiryPolicyDelete(ctx context.Context, arg ExpiryPolicyDeleteParams) (ExpiryPolicy, error)
+	ExpiryPolicyFind(ctx context.Context, arg ExpiryPolicyFindParams) (ExpiryPolicy, error)
+	ExpiryPolicyList(ctx context.Context, userID uuid.UUID) ([]ExpiryPolicy, error)
+	ExpiryPolicyUpdate(ctx context.Context, arg ExpiryPolicyUpdateParams) (ExpiryPolicy, error)
 	TagCreate(ctx context.Context, arg TagCreateParams) (Tag, error)
 	TagDelete(ctx context.Context, arg TagDeleteParams) (Tag, error)
 	TagFind(ctx context.Context, arg TagFindParams) (Tag, error)
 	TagFindAll(ctx context.Context, arg TagFindAllParams) ([]Tag, error)
+	TagFindByNames(ctx context.Context, arg TagFindByNamesParams) ([]Tag, error)
 	TagList(ctx context.Context, userID uuid.UUID) ([]Tag, error)
 	TagMove(ctx context.Context, arg TagMoveParams) (Tag, error)
+	TagRuleCreate(ctx context.Context, arg TagRuleCreateParams) (TagRule, error)
+	TagRuleDelete(ctx context.Context, arg TagRuleDeleteParams) (TagRule, error)
+	TagRuleFind(ctx context.Context, arg TagRuleFindParams) (TagRule, error)
+	TagRuleList(ctx context.Context, userID uuid.UUID) ([]TagRule, error)
+	TagRuleUpdate(ctx context.Context, arg TagRuleUpdateParams) (TagRule, error)
+	TagRulesRetag(ctx context.Context, arg TagRulesRetagParams) ([]TagRule, error)
+	TagSetParent(ctx context.Context, arg TagSetParentParams) (Tag, error)
+	TagUpdate(ctx context.Context, arg TagUpdateParams) (Tag, error)
+	TagsCreateMissing(ctx context.Context, arg TagsCreateMissingParams) ([]Tag, error)
 	TagsDrop(ctx context.Context, arg TagsDropParams) ([]Tag, error)
 	TagsDrops(ctx context.Context, arg TagsDropsParams) ([]TagsDropsRow, error)
+	TagsReparent(ctx context.Context, arg TagsReparentParams) ([]Tag, error)
 	UserCreate(ctx context.Context, emailAddress string) (User, error)
 	UserFind(ctx context.Context, id uuid.UUID) (User, error)
+	ViewCreate(ctx context.Context, arg ViewCreateParams) (View, error)
+	ViewDelete(ctx context.Context, arg ViewDeleteParams) (View, error)
+	ViewFind(ctx context.Context, arg ViewFindParams) (View, error)
+	ViewFindByName(ctx context.Context, arg ViewFindByNameParams) (View, error)
+	ViewList(ctx context.Context, userID uuid.UUID) ([]View, error)
+	ViewUpdate(ctx context.Context, arg ViewUpdateParams) (View, error)
 }
 
 var _ Querier = (*Queries)(nil)
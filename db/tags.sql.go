@@ -5,6 +5,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -12,7 +13,7 @@ import (
 )
 
 const tagCreate = `-- name: TagCreate :one
-insert into tags (user_id, name) values ($1, $2) returning id, user_id, name, created_at, updated_at
+insert into tags (user_id, name) values ($1, $2) returning id, user_id, name, created_at, updated_at, parent_id, color, description
 `
 
 type TagCreateParams struct {
@@ -29,12 +30,15 @@ func (q *Queries) TagCreate(ctx context.Context, arg TagCreateParams) (Tag, erro
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.Color,
+		&i.Description,
 	)
 	return i, err
 }
 
 const tagDelete = `-- name: TagDelete :one
-delete from tags where user_id = $1 and id = $2 returning id, user_id, name, created_at, updated_at
+delete from tags where user_id = $1 and id = $2 returning id, user_id, name, created_at, updated_at, parent_id, color, description
 `
 
 type TagDeleteParams struct {
@@ -51,12 +55,15 @@ func (q *Queries) TagDelete(ctx context.Context, arg TagDeleteParams) (Tag, erro
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.Color,
+		&i.Description,
 	)
 	return i, err
 }
 
 const tagFind = `-- name: TagFind :one
-select id, user_id, name, created_at, updated_at from tags where user_id = $1 and id = $2
+select id, user_id, name, created_at, updated_at, parent_id, color, description from tags where user_id = $1 and id = $2
 `
 
 type TagFindParams struct {
@@ -73,12 +80,15 @@ func (q *Queries) TagFind(ctx context.Context, arg TagFindParams) (Tag, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.Color,
+		&i.Description,
 	)
 	return i, err
 }
 
 const tagFindAll = `-- name: TagFindAll :many
-select id, user_id, name, created_at, updated_at from tags where user_id = $1 and id = ANY($2::uuid[])
+select id, user_id, name, created_at, updated_at, parent_id, color, description from tags where user_id = $1 and id = ANY($2::uuid[])
 `
 
 type TagFindAllParams struct {
@@ -101,6 +111,50 @@ func (q *Queries) TagFindAll(ctx context.Context, arg TagFindAllParams) ([]Tag,
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const tagFindByNames = `-- name: TagFindByNames :many
+select id, user_id, name, created_at, updated_at, parent_id, color, description from tags where user_id = $1 and name = ANY($2::text[])
+`
+
+type TagFindByNamesParams struct {
+	UserID uuid.UUID
+	Names  []string
+}
+
+func (q *Queries) TagFindByNames(ctx context.Context, arg TagFindByNamesParams) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, tagFindByNames, arg.UserID, pq.Array(arg.Names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
 		); err != nil {
 			return nil, err
 		}
@@ -116,7 +170,7 @@ func (q *Queries) TagFindAll(ctx context.Context, arg TagFindAllParams) ([]Tag,
 }
 
 const tagList = `-- name: TagList :many
-select id, user_id, name, created_at, updated_at from tags where user_id = $1
+select id, user_id, name, created_at, updated_at, parent_id, color, description from tags where user_id = $1
 `
 
 func (q *Queries) TagList(ctx context.Context, userID uuid.UUID) ([]Tag, error) {
@@ -134,6 +188,9 @@ func (q *Queries) TagList(ctx context.Context, userID uuid.UUID) ([]Tag, error)
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
 		); err != nil {
 			return nil, err
 		}
@@ -149,7 +206,7 @@ func (q *Queries) TagList(ctx context.Context, userID uuid.UUID) ([]Tag, error)
 }
 
 const tagMove = `-- name: TagMove :one
-update tags set name = $3 where user_id = $1 and id = $2 returning id, user_id, name, created_at, updated_at
+update tags set name = $3 where user_id = $1 and id = $2 returning id, user_id, name, created_at, updated_at, parent_id, color, description
 `
 
 type TagMoveParams struct {
@@ -167,12 +224,121 @@ func (q *Queries) TagMove(ctx context.Context, arg TagMoveParams) (Tag, error) {
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.Color,
+		&i.Description,
+	)
+	return i, err
+}
+
+const tagSetParent = `-- name: TagSetParent :one
+update tags set parent_id = $3 where user_id = $1 and id = $2 returning id, user_id, name, created_at, updated_at, parent_id, color, description
+`
+
+type TagSetParentParams struct {
+	UserID   uuid.UUID
+	ID       uuid.UUID
+	ParentID uuid.NullUUID
+}
+
+func (q *Queries) TagSetParent(ctx context.Context, arg TagSetParentParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, tagSetParent, arg.UserID, arg.ID, arg.ParentID)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.Color,
+		&i.Description,
+	)
+	return i, err
+}
+
+const tagUpdate = `-- name: TagUpdate :one
+update tags set color = $3, description = $4
+where user_id = $1 and id = $2
+returning id, user_id, name, created_at, updated_at, parent_id, color, description
+`
+
+type TagUpdateParams struct {
+	UserID      uuid.UUID
+	ID          uuid.UUID
+	Color       sql.NullString
+	Description sql.NullString
+}
+
+func (q *Queries) TagUpdate(ctx context.Context, arg TagUpdateParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, tagUpdate,
+		arg.UserID,
+		arg.ID,
+		arg.Color,
+		arg.Description,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.Color,
+		&i.Description,
 	)
 	return i, err
 }
 
+const tagsCreateMissing = `-- name: TagsCreateMissing :many
+insert into tags (user_id, name)
+select $1, unnest($2::text[])
+on conflict (user_id, name) do nothing
+returning id, user_id, name, created_at, updated_at, parent_id, color, description
+`
+
+type TagsCreateMissingParams struct {
+	UserID uuid.UUID
+	Names  []string
+}
+
+// Creates the tags that don't exist yet. Use TagFindByNames afterwards to get
+// all of them, including ones another transaction just created.
+func (q *Queries) TagsCreateMissing(ctx context.Context, arg TagsCreateMissingParams) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, tagsCreateMissing, arg.UserID, pq.Array(arg.Names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const tagsDrop = `-- name: TagsDrop :many
-select tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at from tags
+select tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at, tags.parent_id, tags.color, tags.description from tags
 join drop_tags on drop_tags.tag_id = tags.id
 join drops on drops.id = drop_tags.drop_id
 where drops.user_id = $1 and drops.id = $2
@@ -198,6 +364,9 @@ func (q *Queries) TagsDrop(ctx context.Context, arg TagsDropParams) ([]Tag, erro
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
 		); err != nil {
 			return nil, err
 		}
@@ -213,7 +382,7 @@ func (q *Queries) TagsDrop(ctx context.Context, arg TagsDropParams) ([]Tag, erro
 }
 
 const tagsDrops = `-- name: TagsDrops :many
-select tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at, drops.id as drop_id from tags
+select tags.id, tags.user_id, tags.name, tags.created_at, tags.updated_at, tags.parent_id, tags.color, tags.description, drops.id as drop_id from tags
 join drop_tags on drop_tags.tag_id = tags.id
 join drops on drops.id = drop_tags.drop_id
 where drops.user_id = $1 and drops.id = ANY($2::uuid[])
@@ -225,12 +394,15 @@ type TagsDropsParams struct {
 }
 
 type TagsDropsRow struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Name      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DropID    uuid.UUID
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ParentID    uuid.NullUUID
+	Color       sql.NullString
+	Description sql.NullString
+	DropID      uuid.UUID
 }
 
 func (q *Queries) TagsDrops(ctx context.Context, arg TagsDropsParams) ([]TagsDropsRow, error) {
@@ -248,6 +420,9 @@ func (q *Queries) TagsDrops(ctx context.Context, arg TagsDropsParams) ([]TagsDro
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
 			&i.DropID,
 		); err != nil {
 			return nil, err
@@ -262,3 +437,47 @@ func (q *Queries) TagsDrops(ctx context.Context, arg TagsDropsParams) ([]TagsDro
 	}
 	return items, nil
 }
+
+const tagsReparent = `-- name: TagsReparent :many
+update tags set parent_id = $2
+where user_id = $1 and parent_id = any($3::uuid[])
+returning id, user_id, name, created_at, updated_at, parent_id, color, description
+`
+
+type TagsReparentParams struct {
+	UserID       uuid.UUID
+	ParentID     uuid.NullUUID
+	OldParentIds []uuid.UUID
+}
+
+func (q *Queries) TagsReparent(ctx context.Context, arg TagsReparentParams) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, tagsReparent, arg.UserID, arg.ParentID, pq.Array(arg.OldParentIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+			&i.Color,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,244 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// MaxBulkSize is the most drops one bulk operation can change. Filters that
+// match more than this only change the first MaxBulkSize drops, in list
+// order.
+const MaxBulkSize = 500
+
+// A Selection picks the drops for a bulk operation: either a list of IDs, or
+// the drops that List would return for a filter.
+type Selection struct {
+	IDs    []uuid.UUID `json:"ids,omitempty"`
+	Filter *ListBody   `json:"filter,omitempty"`
+}
+
+func (s Selection) Validate() error {
+	if len(s.IDs) == 0 && s.Filter == nil {
+		return api.ValidationError("ids", "", "must be set if there is no filter")
+	}
+	if len(s.IDs) > 0 && s.Filter != nil {
+		return api.ValidationError("filter", "", "must not be set if there are ids")
+	}
+	if len(s.IDs) > MaxBulkSize {
+		return api.ValidationError("ids", fmt.Sprint(len(s.IDs)), fmt.Sprintf("must not have more than %d items", MaxBulkSize))
+	}
+	if s.Filter != nil {
+		return s.Filter.Validate()
+	}
+	return nil
+}
+
+// A BulkResult is the outcome of a bulk operation for one drop. Either Drop
+// or Error is set, unless another drop failed: then nothing was changed, and
+// neither is set for the drops that would have succeeded.
+type BulkResult struct {
+	ID    string     `json:"id"`
+	Drop  *Drop      `json:"drop,omitempty"`
+	Error *api.Error `json:"error,omitempty"`
+}
+
+// BulkMove moves all the selected drops to the status.
+func BulkMove(ctx context.Context, q db.Queryable, user api.User, sel Selection, status Status, now time.Time) ([]BulkResult, error) {
+	return bulk(ctx, q, user, sel, now, func(id uuid.UUID) (Drop, error) {
+		return Move(ctx, q, user, id, status, now)
+	})
+}
+
+// BulkTag adds and removes tags on all the selected drops. Tags the drops
+// already have (or don't have) are skipped.
+func BulkTag(ctx context.Context, q db.Queryable, user api.User, sel Selection, add, remove []uuid.UUID, now time.Time) ([]BulkResult, error) {
+	ids := append(append([]uuid.UUID{}, add...), remove...)
+	if err := checkTags(ctx, q, user, ids); err != nil {
+		return nil, err
+	}
+
+	return bulk(ctx, q, user, sel, now, func(id uuid.UUID) (Drop, error) {
+		return retag(ctx, q, user, id, add, remove)
+	})
+}
+
+// BulkDelete moves all the selected drops to the trash.
+func BulkDelete(ctx context.Context, q db.Queryable, user api.User, sel Selection, now time.Time) ([]BulkResult, error) {
+	return bulk(ctx, q, user, sel, now, func(id uuid.UUID) (Drop, error) {
+		return Delete(ctx, q, user, id, now)
+	})
+}
+
+// bulk calls fn with each selected drop, all or nothing. If any drops don't
+// exist or fail with an API error, every change is rolled back and those drops
+// get error results. Any other error fails the whole operation.
+func bulk(ctx context.Context, q db.Queryable, user api.User, sel Selection, now time.Time, fn func(uuid.UUID) (Drop, error)) ([]BulkResult, error) {
+	ids, err := selectIDs(ctx, q, user, sel, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ExecContext(ctx, "SAVEPOINT bulk"); err != nil {
+		return nil, err
+	}
+	res := make([]BulkResult, 0, len(ids))
+	failed := false
+	for _, id := range ids {
+		d, err := fn(id)
+		var aerr api.Error
+		switch {
+		case err == nil:
+			res = append(res, BulkResult{ID: id.String(), Drop: &d})
+		case errors.Is(err, sql.ErrNoRows):
+			aerr = api.NoResourceError("drop", id.String())
+			res = append(res, BulkResult{ID: id.String(), Error: &aerr})
+			failed = true
+		case errors.As(err, &aerr):
+			res = append(res, BulkResult{ID: id.String(), Error: &aerr})
+			failed = true
+		default:
+			return nil, err
+		}
+	}
+	if !failed {
+		_, err := q.ExecContext(ctx, "RELEASE SAVEPOINT bulk")
+		return res, err
+	}
+
+	if _, err := q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk"); err != nil {
+		return nil, err
+	}
+	for i := range res {
+		res[i].Drop = nil
+	}
+	return res, nil
+}
+
+// selectIDs returns the IDs of the selected drops, without duplicates.
+func selectIDs(ctx context.Context, q db.Queryable, user api.User, sel Selection, now time.Time) ([]uuid.UUID, error) {
+	if sel.Filter == nil {
+		var ids []uuid.UUID
+		seen := make(map[uuid.UUID]bool, len(sel.IDs))
+		for _, id := range sel.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	f := *sel.Filter
+	limit := int32(MaxBulkSize)
+	if f.Limit != nil && 0 < *f.Limit && *f.Limit < limit {
+		limit = *f.Limit
+	}
+	f.Limit = &limit
+	ds, err := Filter(ctx, q, user, f, now)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(ds))
+	for _, d := range ds {
+		ids = append(ids, uuid.FromStringOrNil(d.ID))
+	}
+	return ids, nil
+}
+
+// retag adds and removes tags on a drop, recording a tag event if that
+// changed anything. The tags must belong to the user.
+func retag(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, add, remove []uuid.UUID) (Drop, error) {
+	before, err := Get(ctx, q, user, id)
+	if err != nil {
+		return Drop{}, err
+	}
+
+	changed := false
+	have := tagSet(before.Tags)
+	for _, tagID := range add {
+		if have[tagID.String()] {
+			continue
+		}
+		_, err := q.DropTagApply(ctx, db.DropTagApplyParams{
+			DropID: id,
+			TagID:  tagID,
+		})
+		if err != nil {
+			return Drop{}, err
+		}
+		have[tagID.String()] = true
+		changed = true
+	}
+	if len(remove) > 0 {
+		dts, err := q.DropTagsRemove(ctx, db.DropTagsRemoveParams{
+			DropID: id,
+			TagIds: remove,
+		})
+		if err != nil {
+			return Drop{}, err
+		}
+		changed = changed || len(dts) > 0
+	}
+	if !changed {
+		return before, nil
+	}
+
+	after, err := Get(ctx, q, user, id)
+	if err != nil {
+		return Drop{}, err
+	}
+	return after, recordEvent(ctx, q, user.ID, EventTag, &before, &after)
+}
+
+// Retagged records a tag event for every drop that has one of the given tags,
+// for changes made outside this package, like merging or deleting tags. It
+// takes a snapshot of those drops, calls change to edit their tags, and then
+// records how each one ended up. Drops in the trash are included.
+func Retagged(ctx context.Context, q db.Queryable, user api.User, tagIDs []uuid.UUID, change func() error) error {
+	ds, err := q.DropsTagged(ctx, db.DropsTaggedParams{
+		UserID: user.ID,
+		TagIds: tagIDs,
+	})
+	if err != nil {
+		return err
+	}
+	before := make([]Drop, len(ds))
+	for i, d := range ds {
+		if before[i], err = loadOne(ctx, q, user, d); err != nil {
+			return err
+		}
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	// Only the tags change, so the rows from before are still current.
+	for i, d := range ds {
+		after, err := loadOne(ctx, q, user, d)
+		if err != nil {
+			return err
+		}
+		if err := recordEvent(ctx, q, user.ID, EventTag, &before[i], &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tagSet returns the IDs of the tags as a set.
+func tagSet(ts []Tag) map[string]bool {
+	set := make(map[string]bool, len(ts))
+	for _, t := range ts {
+		set[t.ID] = true
+	}
+	return set
+}
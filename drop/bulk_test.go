@@ -0,0 +1,108 @@
+package drop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func TestBulk(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	ml, err := tag.Create(ctx, q, user, "ml")
+	require.NoError(t, err)
+	mlID := uuid.FromStringOrNil(ml.ID)
+
+	var ids []uuid.UUID
+	for _, url := range []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"} {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: url}, clock.Now())
+		require.NoError(t, err)
+		ids = append(ids, uuid.FromStringOrNil(d.ID))
+	}
+	missing := uuid.Must(uuid.NewV4())
+
+	// One missing drop means none of them change.
+	bad := drop.Selection{IDs: []uuid.UUID{ids[0], ids[1], missing, ids[0]}}
+	rs, err := drop.BulkMove(ctx, q, user, bad, drop.StatusRead, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, rs, 3)
+	assert.Nil(t, rs[0].Drop)
+	assert.Nil(t, rs[0].Error)
+	assert.Equal(t, missing.String(), rs[2].ID)
+	require.NotNil(t, rs[2].Error)
+	assert.Nil(t, rs[2].Drop)
+	for _, id := range ids[:2] {
+		d, err := drop.Get(ctx, q, user, id)
+		require.NoError(t, err)
+		assert.Equal(t, drop.StatusUnread, d.Status)
+	}
+
+	sel := drop.Selection{IDs: []uuid.UUID{ids[0], ids[1], ids[0]}}
+	rs, err = drop.BulkMove(ctx, q, user, sel, drop.StatusRead, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	assert.Equal(t, drop.StatusRead, rs[0].Drop.Status)
+	assert.Equal(t, drop.StatusRead, rs[1].Drop.Status)
+
+	rs, err = drop.BulkTag(ctx, q, user, sel, []uuid.UUID{mlID}, nil, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	require.Len(t, rs[0].Drop.Tags, 1)
+	assert.Equal(t, ml.ID, rs[0].Drop.Tags[0].ID)
+
+	// Adding a tag twice doesn't duplicate it.
+	rs, err = drop.BulkTag(ctx, q, user, sel, []uuid.UUID{mlID}, nil, clock.Now())
+	require.NoError(t, err)
+	assert.Len(t, rs[0].Drop.Tags, 1)
+
+	rs, err = drop.BulkTag(ctx, q, user, drop.Selection{IDs: ids[:1]}, nil, []uuid.UUID{mlID}, clock.Now())
+	require.NoError(t, err)
+	assert.Empty(t, rs[0].Drop.Tags)
+
+	_, err = drop.BulkTag(ctx, q, user, sel, []uuid.UUID{missing}, nil, clock.Now())
+	assert.Error(t, err)
+
+	// Only the second drop still has the tag.
+	filter := drop.Selection{Filter: &drop.ListBody{Tags: &[]uuid.UUID{mlID}}}
+	rs, err = drop.BulkDelete(ctx, q, user, filter, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	assert.Equal(t, ids[1].String(), rs[0].ID)
+	assert.NotNil(t, rs[0].Drop.DeletedAt)
+}
+
+func TestSelectionValidate(t *testing.T) {
+	id := uuid.Must(uuid.NewV4())
+	assert.Error(t, drop.Selection{}.Validate())
+	assert.Error(t, drop.Selection{IDs: []uuid.UUID{id}, Filter: &drop.ListBody{}}.Validate())
+	assert.NoError(t, drop.Selection{IDs: []uuid.UUID{id}}.Validate())
+	assert.NoError(t, drop.Selection{Filter: &drop.ListBody{}}.Validate())
+
+	assert.Error(t, drop.BulkMoveBody{Selection: drop.Selection{IDs: []uuid.UUID{id}}}.Validate())
+	err := drop.BulkMoveBody{Selection: drop.Selection{IDs: []uuid.UUID{id}}, Status: drop.StatusSnoozed}.Validate()
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, `field "status" (value "snoozed"): must be one of: unread, read, saved, expired`, aerr.Message)
+	assert.NoError(t, drop.BulkMoveBody{Selection: drop.Selection{IDs: []uuid.UUID{id}}, Status: drop.StatusSaved}.Validate())
+	assert.Error(t, drop.BulkTagBody{Selection: drop.Selection{IDs: []uuid.UUID{id}}}.Validate())
+	assert.Error(t, drop.BulkTagBody{
+		Selection:  drop.Selection{IDs: []uuid.UUID{id}},
+		AddTags:    []uuid.UUID{id},
+		RemoveTags: []uuid.UUID{id},
+	}.Validate())
+}
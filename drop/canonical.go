@@ -0,0 +1,51 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/canonical"
+	"github.com/metagram-net/firehose/db"
+)
+
+// canonicalize returns the canonical form of the URL for finding duplicates.
+// Some URLs can't be canonicalized (like "javascript:" bookmarklets), and those
+// are stored without one so they never count as duplicates.
+func canonicalize(urls canonical.Rules, url string) sql.NullString {
+	c, err := urls.URL(url)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return db.NullString(&c)
+}
+
+// findDuplicate returns the user's drop with the canonical URL, or nil if there
+// isn't one.
+func findDuplicate(ctx context.Context, q db.Queryable, user api.User, canonicalURL sql.NullString) (*db.Drop, error) {
+	if !canonicalURL.Valid {
+		return nil, nil
+	}
+	d, err := q.DropFindByCanonicalURL(ctx, db.DropFindByCanonicalURLParams{
+		UserID:       user.ID,
+		CanonicalURL: canonicalURL,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// duplicateError returns an error with the existing drop in the details, so
+// clients can show it instead.
+func duplicateError(ctx context.Context, q db.Queryable, user api.User, existing db.Drop) error {
+	d, err := loadOne(ctx, q, user, existing)
+	if err != nil {
+		return err
+	}
+	return api.DuplicateError("drop", d.ID, &d)
+}
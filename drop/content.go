@@ -0,0 +1,134 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/metadata"
+	"github.com/metagram-net/firehose/readable"
+)
+
+// maxContentSize limits how much of a page is read for its content.
+const maxContentSize = 5 << 20
+
+// Content is a snapshot of the readable part of a drop's page. If the page
+// couldn't be read, Error says why and the rest is empty.
+type Content struct {
+	DropID    string    `json:"drop_id"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title,omitempty"`
+	Text      string    `json:"text"`
+	HTML      string    `json:"html"`
+	Error     string    `json:"error,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+func contentModel(c db.DropContent) Content {
+	return Content{
+		DropID:    c.DropID.String(),
+		URL:       c.URL,
+		Title:     c.Title,
+		Text:      c.Text,
+		HTML:      c.HTML,
+		Error:     c.Error.String,
+		FetchedAt: c.FetchedAt,
+	}
+}
+
+// GetContent returns the saved content of a drop. Content is fetched in the
+// background, so this returns a not-found error until that's happened.
+func GetContent(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Content, error) {
+	c, err := q.DropContentFind(ctx, db.DropContentFindParams{
+		UserID: user.ID,
+		DropID: id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Content{}, api.NoResourceError("drop content", id.String())
+	}
+	if err != nil {
+		return Content{}, err
+	}
+	return contentModel(c), nil
+}
+
+// FetchContent saves the readable content of the oldest drop that doesn't
+// have any yet. It returns false if there was nothing to do.
+//
+// Like FetchMetadata, the drop stays locked until the transaction ends.
+func FetchContent(ctx context.Context, q db.Queryable, log *zap.Logger, f *metadata.Fetcher, now time.Time) (bool, error) {
+	d, err := q.DropContentPending(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	params := db.DropContentSaveParams{
+		DropID:    d.ID,
+		UserID:    d.UserID,
+		URL:       d.URL,
+		FetchedAt: now,
+	}
+	if err := extract(ctx, f, &params); err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		// Save the failure so the drop isn't retried forever.
+		log.Info("Could not fetch drop content",
+			zap.Stringer("drop_id", d.ID),
+			zap.Error(err))
+		params.Error = nullString(err.Error())
+	}
+
+	if _, err := q.DropContentSave(ctx, params); err != nil {
+		return false, err
+	}
+	if params.Error.Valid {
+		return true, nil
+	}
+
+	words := len(strings.Fields(params.Text))
+	_, err = q.DropSetReadingTime(ctx, db.DropSetReadingTimeParams{
+		ID:             d.ID,
+		WordCount:      sql.NullInt32{Int32: int32(words), Valid: true},
+		ReadingMinutes: sql.NullInt32{Int32: readingMinutes(words), Valid: true},
+	})
+	return true, err
+}
+
+// wordsPerMinute is a typical adult reading speed for non-fiction.
+const wordsPerMinute = 238
+
+// readingMinutes estimates how long it takes to read some words, rounded up
+// to the next whole minute.
+func readingMinutes(words int) int32 {
+	return int32((words + wordsPerMinute - 1) / wordsPerMinute)
+}
+
+func extract(ctx context.Context, f *metadata.Fetcher, params *db.DropContentSaveParams) error {
+	body, u, err := f.Open(ctx, params.URL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	a, err := readable.Extract(io.LimitReader(body, maxContentSize), u)
+	if err != nil {
+		return err
+	}
+	params.URL = u.String()
+	params.Title = a.Title
+	params.Text = a.Text
+	params.HTML = a.HTML
+	return nil
+}
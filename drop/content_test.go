@@ -0,0 +1,104 @@
+package drop_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/metadata"
+)
+
+func TestFetchContent(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, time.Second)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><head><title>Example Page</title></head><body>
+			<nav><a href="/">Home</a></nav>
+			<article><p>The main text of the page, which goes on for long enough to count.</p></article>
+		</body></html>`)
+	}))
+	t.Cleanup(srv.Close)
+
+	page, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Page", URL: srv.URL + "/page"}, clock.Now())
+	require.NoError(t, err)
+	gone, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Gone", URL: srv.URL + "/gone"}, clock.Now())
+	require.NoError(t, err)
+
+	f := metadata.NewFetcher(true)
+	fetchAll := func() {
+		for {
+			more, err := drop.FetchContent(ctx, q, zap.NewNop(), f, clock.Now())
+			require.NoError(t, err)
+			if !more {
+				break
+			}
+		}
+	}
+	fetchAll()
+
+	id := uuid.FromStringOrNil(page.ID)
+	c, err := drop.GetContent(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Example Page", c.Title)
+	assert.Equal(t, "The main text of the page, which goes on for long enough to count.", c.Text)
+	assert.Empty(t, c.Error)
+
+	c, err = drop.GetContent(ctx, q, user, uuid.FromStringOrNil(gone.ID))
+	require.NoError(t, err)
+	assert.Empty(t, c.Text)
+	assert.Contains(t, c.Error, "404")
+
+	// The reading time is estimated from the content.
+	d, err := drop.Get(ctx, q, user, id)
+	require.NoError(t, err)
+	require.NotNil(t, d.WordCount)
+	assert.Equal(t, int32(14), *d.WordCount)
+	require.NotNil(t, d.ReadingMinutes)
+	assert.Equal(t, int32(1), *d.ReadingMinutes)
+	d, err = drop.Get(ctx, q, user, uuid.FromStringOrNil(gone.ID))
+	require.NoError(t, err)
+	assert.Nil(t, d.WordCount)
+	assert.Nil(t, d.ReadingMinutes)
+
+	limit := int32(10)
+	ds, err := drop.Filter(ctx, q, user, drop.ListBody{Status: drop.StatusUnread, Limit: &limit, MaxReadingMinutes: &limit}, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	assert.Equal(t, page.ID, ds[0].ID)
+
+	// Changing the URL throws out the old content until it's fetched again.
+	url := srv.URL + "/moved"
+	_, err = drop.Update(ctx, q, user, nil, id, drop.UpdateFields{URL: &url})
+	require.NoError(t, err)
+	_, err = drop.GetContent(ctx, q, user, id)
+	assert.ErrorIs(t, err, api.NoResourceError("drop content", id.String()))
+	d, err = drop.Get(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Nil(t, d.ReadingMinutes)
+
+	fetchAll()
+	c, err = drop.GetContent(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Equal(t, url, c.URL)
+}
@@ -0,0 +1,99 @@
+package drop
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// A Cursor marks a position in a list of drops ordered by (pinned_at, moved_at,
+// id), with unpinned drops last.
+//
+// Cursors are opaque to clients: they are encoded as base64 text so the
+// fields can change without breaking anyone who stored one.
+type Cursor struct {
+	// PinnedAt is nil for unpinned drops. Cursors from before pinning existed
+	// don't have it, which is fine: they were all unpinned.
+	PinnedAt *time.Time `json:"pinned_at,omitempty"`
+	MovedAt  time.Time  `json:"moved_at"`
+	ID       uuid.UUID  `json:"id"`
+}
+
+func cursorAfter(d Drop) *Cursor {
+	return &Cursor{
+		PinnedAt: d.PinnedAt,
+		MovedAt:  d.MovedAt,
+		ID:       uuid.FromStringOrNil(d.ID),
+	}
+}
+
+// key returns the values to compare with (pinned_at is null,
+// coalesce(pinned_at, '0001-01-01'), moved_at, id) to find the drops after
+// the cursor. A nil cursor is before every drop.
+func (c *Cursor) key() (unpinned bool, pinnedAt, movedAt time.Time, id uuid.UUID) {
+	if c == nil {
+		return false, time.Time{}, time.Time{}, uuid.Nil
+	}
+	if c.PinnedAt != nil {
+		pinnedAt = *c.PinnedAt
+	}
+	return c.PinnedAt == nil, pinnedAt, c.MovedAt, c.ID
+}
+
+// cursorFields has the same fields as Cursor but none of the methods, so the
+// JSON encoding inside the text doesn't recurse into MarshalText.
+type cursorFields Cursor
+
+// Implement encoding.TextMarshaler and encoding.TextUnmarshaler
+
+func (c Cursor) MarshalText() ([]byte, error) {
+	b, err := json.Marshal(cursorFields(c))
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, base64.RawURLEncoding.EncodedLen(len(b)))
+	base64.RawURLEncoding.Encode(buf, b)
+	return buf, nil
+}
+
+func (c *Cursor) UnmarshalText(text []byte) error {
+	b := make([]byte, base64.RawURLEncoding.DecodedLen(len(text)))
+	n, err := base64.RawURLEncoding.Decode(b, text)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCursor, err.Error())
+	}
+
+	var v cursorFields
+	if err := json.Unmarshal(b[:n], &v); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCursor, err.Error())
+	}
+	*c = Cursor(v)
+	return nil
+}
+
+// Implement pflag.Value
+
+func (c *Cursor) String() string {
+	if c == nil {
+		return ""
+	}
+	b, err := c.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (c *Cursor) Set(s string) error {
+	return c.UnmarshalText([]byte(s))
+}
+
+func (*Cursor) Type() string {
+	return "cursor"
+}
@@ -0,0 +1,51 @@
+package drop_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		c := drop.Cursor{
+			MovedAt: apitest.Clock(t).Now().Add(123 * time.Microsecond),
+			ID:      apitest.UUID(t),
+		}
+
+		b, err := json.Marshal(drop.ListBody{Cursor: &c})
+		require.NoError(t, err)
+
+		var body drop.ListBody
+		require.NoError(t, json.Unmarshal(b, &body))
+		require.NotNil(t, body.Cursor)
+		assert.True(t, c.MovedAt.Equal(body.Cursor.MovedAt))
+		assert.Equal(t, c.ID, body.Cursor.ID)
+		assert.Nil(t, body.Cursor.PinnedAt)
+	})
+
+	t.Run("pinned", func(t *testing.T) {
+		pinned := apitest.Clock(t).Now()
+		c := drop.Cursor{
+			PinnedAt: &pinned,
+			MovedAt:  pinned.Add(-time.Hour),
+			ID:       apitest.UUID(t),
+		}
+
+		var got drop.Cursor
+		require.NoError(t, got.Set(c.String()))
+		require.NotNil(t, got.PinnedAt)
+		assert.True(t, pinned.Equal(*got.PinnedAt))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var c drop.Cursor
+		assert.ErrorIs(t, c.Set("not a cursor"), drop.ErrInvalidCursor)
+	})
+}
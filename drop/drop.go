@@ -4,23 +4,37 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
-	"github.com/blockloop/scan"
 	"github.com/gofrs/uuid"
 
 	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/canonical"
 	"github.com/metagram-net/firehose/db"
 )
 
 type Drop struct {
-	ID      string    `json:"id"`
-	Title   string    `json:"title"`
-	URL     string    `json:"url"`
-	Status  Status    `json:"status"`
-	MovedAt time.Time `json:"moved_at"`
-	Tags    []Tag     `json:"tags"`
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	URL         string      `json:"url"`
+	Status      Status      `json:"status"`
+	MovedAt     time.Time   `json:"moved_at"`
+	Tags        []Tag       `json:"tags"`
+	Description string      `json:"description,omitempty"`
+	SiteName    string      `json:"site_name,omitempty"`
+	ImageURL    string      `json:"image_url,omitempty"`
+	SnoozeUntil *time.Time  `json:"snooze_until,omitempty"`
+	Notes       string      `json:"notes,omitempty"`
+	Highlights  []Highlight `json:"highlights"`
+	DeletedAt   *time.Time  `json:"deleted_at,omitempty"`
+	// WordCount and ReadingMinutes are estimated from the readable content,
+	// so they're only set once that has been fetched.
+	WordCount      *int32 `json:"word_count,omitempty"`
+	ReadingMinutes *int32 `json:"reading_minutes,omitempty"`
+	// PinnedAt is set for pinned drops, which jump the queue.
+	PinnedAt *time.Time `json:"pinned_at,omitempty"`
 }
 
 type Tag struct {
@@ -28,7 +42,7 @@ type Tag struct {
 	Name string `json:"name"`
 }
 
-func model(d db.Drop, ts []db.Tag) Drop {
+func model(d db.Drop, ts []db.Tag, hs []db.DropHighlight) Drop {
 	tags := make([]Tag, 0)
 	for _, t := range ts {
 		tags = append(tags, Tag{
@@ -36,17 +50,79 @@ func model(d db.Drop, ts []db.Tag) Drop {
 			Name: t.Name,
 		})
 	}
+	highlights := make([]Highlight, 0)
+	for _, h := range hs {
+		highlights = append(highlights, highlightModel(h))
+	}
 	return Drop{
-		ID:      d.ID.String(),
-		Title:   d.Title.String,
-		URL:     d.URL,
-		Status:  StatusModel(d.Status),
-		MovedAt: d.MovedAt,
-		Tags:    tags,
+		ID:          d.ID.String(),
+		Title:       d.Title.String,
+		URL:         d.URL,
+		Status:      StatusModel(d.Status),
+		MovedAt:     d.MovedAt,
+		Tags:        tags,
+		Description: d.Description.String,
+		SiteName:    d.SiteName.String,
+		ImageURL:    d.ImageURL.String,
+		SnoozeUntil: nullTime(d.SnoozeUntil),
+		Notes:       d.Notes.String,
+		Highlights:  highlights,
+		DeletedAt:   nullTime(d.DeletedAt),
+
+		WordCount:      nullInt32(d.WordCount),
+		ReadingMinutes: nullInt32(d.ReadingMinutes),
+		PinnedAt:       nullTime(d.PinnedAt),
 	}
 }
 
-func Create(ctx context.Context, q db.Queryable, user api.User, title string, url string, tagIDs []uuid.UUID, now time.Time) (Drop, error) {
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullInt32(n sql.NullInt32) *int32 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int32
+}
+
+type CreateFields struct {
+	Title  string
+	URL    string
+	TagIDs []uuid.UUID
+	// TagNames are more tags to add, by name. Any that don't exist yet are
+	// created.
+	TagNames []string
+
+	// Bump moves an existing drop with the same canonical URL back to unread,
+	// instead of returning a duplicate error.
+	Bump bool
+}
+
+// Create creates a new unread drop, tagged with the given tags and those of
+// any of the user's tag rules that match it. If the user already has a drop
+// with the same canonical URL, this returns a duplicate error (or bumps the
+// existing drop, if requested) instead.
+func Create(ctx context.Context, q db.Queryable, user api.User, urls canonical.Rules, f CreateFields, now time.Time) (Drop, error) {
+	canonicalURL := canonicalize(urls, f.URL)
+	dup, err := findDuplicate(ctx, q, user, canonicalURL)
+	if err != nil {
+		return Drop{}, err
+	}
+	if dup != nil {
+		if f.Bump {
+			return Move(ctx, q, user, dup.ID, StatusUnread, now)
+		}
+		return Drop{}, duplicateError(ctx, q, user, *dup)
+	}
+
+	tagIDs, err := withNamedTags(ctx, q, user, f.TagIDs, f.TagNames)
+	if err != nil {
+		return Drop{}, err
+	}
 	var ts []db.Tag
 	if len(tagIDs) > 0 {
 		var err error
@@ -60,11 +136,12 @@ func Create(ctx context.Context, q db.Queryable, user api.User, title string, ur
 	}
 
 	d, err := q.DropCreate(ctx, db.DropCreateParams{
-		UserID:  user.ID,
-		Title:   db.NullString(&title),
-		URL:     url,
-		Status:  db.DropStatusUnread,
-		MovedAt: now,
+		UserID:       user.ID,
+		Title:        db.NullString(&f.Title),
+		URL:          f.URL,
+		CanonicalURL: canonicalURL,
+		Status:       db.DropStatusUnread,
+		MovedAt:      now,
 	})
 	if err != nil {
 		return Drop{}, err
@@ -74,26 +151,213 @@ func Create(ctx context.Context, q db.Queryable, user api.User, title string, ur
 	if err != nil {
 		return Drop{}, err
 	}
-	return loadOne(ctx, q, user, d)
+	rs, err := q.TagRuleList(ctx, user.ID)
+	if err != nil {
+		return Drop{}, err
+	}
+	if err := applyRules(ctx, q, rs, d); err != nil {
+		return Drop{}, err
+	}
+
+	res, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return Drop{}, err
+	}
+	return res, recordEvent(ctx, q, user.ID, EventCreate, nil, &res)
+}
+
+// A BatchItem is one drop to create in a batch. Unlike a regular create, the
+// status and timestamps can be set (to preserve history from other apps),
+// and tags are referenced by name.
+type BatchItem struct {
+	Title     string     `json:"title,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Status    Status     `json:"status,omitempty"`
+	MovedAt   *time.Time `json:"moved_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	// SnoozeUntil is when a snoozed drop wakes up. Snoozed items without it
+	// are created as unread, since nothing would ever wake them.
+	SnoozeUntil *time.Time `json:"snooze_until,omitempty"`
+	// PinnedAt is only kept for unread drops, since only those can be pinned.
+	PinnedAt   *time.Time       `json:"pinned_at,omitempty"`
+	Notes      string           `json:"notes,omitempty"`
+	Highlights []BatchHighlight `json:"highlights,omitempty"`
+}
+
+type BatchHighlight struct {
+	Quote     string     `json:"quote"`
+	Comment   string     `json:"comment,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// CreateBatch creates all the drops in the batch. Tags are matched by name, and
+// any that don't exist yet are created. The user's tag rules apply too. Items
+// without a status or timestamps default to unread and now, and snoozed items
+// without a wake-up time are unread too. Notes and highlights are kept, in
+// order.
+//
+// Items with the same canonical URL as an existing drop (or an earlier item in
+// the batch) are skipped, and the existing drop is returned in their place.
+// That way, importing the same file twice doesn't create duplicates.
+func CreateBatch(ctx context.Context, q db.Queryable, user api.User, urls canonical.Rules, items []BatchItem, now time.Time) ([]Drop, error) {
+	var names []string
+	for _, it := range items {
+		names = append(names, it.Tags...)
+	}
+	tags, err := findOrCreateTags(ctx, q, user, names)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := q.TagRuleList(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := make([]db.Drop, 0, len(items))
+	created := make(map[string]db.Drop)
+	isNew := make([]bool, 0, len(items))
+	for _, it := range items {
+		canonicalURL := canonicalize(urls, it.URL)
+		if d, ok := created[canonicalURL.String]; ok && canonicalURL.Valid {
+			ds = append(ds, d)
+			isNew = append(isNew, false)
+			continue
+		}
+		dup, err := findDuplicate(ctx, q, user, canonicalURL)
+		if err != nil {
+			return nil, err
+		}
+		if dup != nil {
+			ds = append(ds, *dup)
+			isNew = append(isNew, false)
+			continue
+		}
+
+		status := it.Status
+		snoozeUntil := it.SnoozeUntil
+		if status == StatusUnknown || (status == StatusSnoozed && snoozeUntil == nil) {
+			status = StatusUnread
+		}
+		if status != StatusSnoozed {
+			snoozeUntil = nil
+		}
+		movedAt := now
+		if it.MovedAt != nil {
+			movedAt = *it.MovedAt
+		}
+		createdAt := now
+		if it.CreatedAt != nil {
+			createdAt = *it.CreatedAt
+		}
+		pinnedAt := it.PinnedAt
+		if status != StatusUnread {
+			pinnedAt = nil
+		}
+
+		title := it.Title
+		d, err := q.DropImport(ctx, db.DropImportParams{
+			UserID:       user.ID,
+			Title:        db.NullString(&title),
+			URL:          it.URL,
+			CanonicalURL: canonicalURL,
+			Status:       status.Model(),
+			MovedAt:      movedAt,
+			CreatedAt:    createdAt,
+			SnoozeUntil:  db.NullTime(snoozeUntil),
+			PinnedAt:     db.NullTime(pinnedAt),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if it.Notes != "" {
+			notes := nullString(it.Notes)
+			d, err = db.DropUpdate(ctx, q, db.DropUpdateFields{
+				Select: db.DropUpdateSelect{
+					ID:     d.ID,
+					UserID: user.ID,
+				},
+				Set: db.DropUpdateSet{
+					Notes: &notes,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i, h := range it.Highlights {
+			// Highlights without a time still need distinct ones to keep
+			// their order.
+			createdAt := now.Add(time.Duration(i) * time.Microsecond)
+			if h.CreatedAt != nil {
+				createdAt = *h.CreatedAt
+			}
+			_, err := q.DropHighlightImport(ctx, db.DropHighlightImportParams{
+				UserID:    user.ID,
+				DropID:    d.ID,
+				Quote:     h.Quote,
+				Comment:   nullString(h.Comment),
+				CreatedAt: createdAt,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		created[canonicalURL.String] = d
+
+		var ts []db.Tag
+		seen := make(map[string]bool)
+		for _, n := range it.Tags {
+			t, ok := tags[strings.TrimSpace(n)]
+			if !ok || seen[t.Name] {
+				continue
+			}
+			seen[t.Name] = true
+			ts = append(ts, t)
+		}
+		if _, err := db.DropTagsApply(ctx, q, d, ts); err != nil {
+			return nil, err
+		}
+		if err := applyRules(ctx, q, rs, d); err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+		isNew = append(isNew, true)
+	}
+
+	res, err := loadMany(ctx, q, user, ds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		if isNew[i] {
+			if err := recordEvent(ctx, q, user.ID, EventCreate, nil, &res[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
 }
 
 type UpdateFields struct {
 	Title *string
 	URL   *string
-	Tags  *[]uuid.UUID
+	// Tags and TagNames together replace the drop's tags. Any tags named in
+	// TagNames that don't exist yet are created.
+	Tags     *[]uuid.UUID
+	TagNames *[]string
+	// Notes replaces the drop's notes. Set it to the empty string to clear
+	// them.
+	Notes *string
 }
 
-func Update(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, f UpdateFields) (Drop, error) {
-	d, err := db.DropUpdate(ctx, q, db.DropUpdateFields{
-		Select: db.DropUpdateSelect{
-			ID:     id,
-			UserID: user.ID,
-		},
-		Set: db.DropUpdateSet{
-			Title: f.Title,
-			URL:   f.URL,
-		},
-	})
+// Update changes the fields of a drop that are set. Changing the URL to one
+// with the same canonical URL as another drop returns a duplicate error.
+//
+// Changes to the title, URL, or notes are recorded as an update event, and
+// changes to the tags as a separate tag event.
+func Update(ctx context.Context, q db.Queryable, user api.User, urls canonical.Rules, id uuid.UUID, f UpdateFields) (Drop, error) {
+	before, err := Get(ctx, q, user, id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return Drop{}, api.NoResourceError("drop", id.String())
 	}
@@ -101,72 +365,153 @@ func Update(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, f
 		return Drop{}, err
 	}
 
-	if f.Tags != nil {
+	tags := f.Tags
+	if f.TagNames != nil {
+		ids := make([]uuid.UUID, 0)
+		if tags != nil {
+			ids = *tags
+		}
+		ids, err = withNamedTags(ctx, q, user, ids, *f.TagNames)
+		if err != nil {
+			return Drop{}, err
+		}
+		tags = &ids
+	}
+	if tags != nil {
+		if err := checkTags(ctx, q, user, *tags); err != nil {
+			return Drop{}, err
+		}
+	}
+
+	var canonicalURL *sql.NullString
+	if f.URL != nil {
+		c := canonicalize(urls, *f.URL)
+		dup, err := findDuplicate(ctx, q, user, c)
+		if err != nil {
+			return Drop{}, err
+		}
+		if dup != nil && dup.ID != id {
+			return Drop{}, duplicateError(ctx, q, user, *dup)
+		}
+		canonicalURL = &c
+	}
+
+	var notes *sql.NullString
+	if f.Notes != nil {
+		n := nullString(*f.Notes)
+		notes = &n
+	}
+
+	after := before
+	if f.Title != nil || f.URL != nil || f.Notes != nil {
+		d, err := db.DropUpdate(ctx, q, db.DropUpdateFields{
+			Select: db.DropUpdateSelect{
+				ID:     id,
+				UserID: user.ID,
+			},
+			Set: db.DropUpdateSet{
+				Title:        f.Title,
+				URL:          f.URL,
+				CanonicalURL: canonicalURL,
+				Notes:        notes,
+			},
+		})
+		if err != nil {
+			return Drop{}, err
+		}
+		after, err = loadOne(ctx, q, user, d)
+		if err != nil {
+			return Drop{}, err
+		}
+		if err := recordEvent(ctx, q, user.ID, EventUpdate, &before, &after); err != nil {
+			return Drop{}, err
+		}
+	}
+
+	// The saved content is for the old page, so clear it out to be fetched
+	// again, along with the estimates based on it.
+	if after.URL != before.URL {
+		_, err := q.DropContentsDelete(ctx, db.DropContentsDeleteParams{
+			UserID: user.ID,
+			DropID: id,
+		})
+		if err != nil {
+			return Drop{}, err
+		}
+		d, err := q.DropSetReadingTime(ctx, db.DropSetReadingTimeParams{
+			ID: id,
+		})
+		if err != nil {
+			return Drop{}, err
+		}
+		after.WordCount = nullInt32(d.WordCount)
+		after.ReadingMinutes = nullInt32(d.ReadingMinutes)
+	}
+
+	if tags != nil {
 		_, err := q.DropTagsIntersect(ctx, db.DropTagsIntersectParams{
-			DropID: d.ID,
-			TagIds: *f.Tags,
+			DropID: id,
+			TagIds: *tags,
 		})
 		if err != nil {
 			return Drop{}, err
 		}
 
 		// TODO: Combine this into one query.
-		for _, tagID := range *f.Tags {
+		have := tagSet(before.Tags)
+		for _, tagID := range *tags {
+			if have[tagID.String()] {
+				continue
+			}
 			_, err := q.DropTagApply(ctx, db.DropTagApplyParams{
-				DropID: d.ID,
+				DropID: id,
 				TagID:  tagID,
 			})
 			if err != nil {
 				return Drop{}, err
 			}
+			have[tagID.String()] = true
+		}
+
+		tagged, err := Get(ctx, q, user, id)
+		if err != nil {
+			return Drop{}, err
+		}
+		if err := recordEvent(ctx, q, user.ID, EventTag, &after, &tagged); err != nil {
+			return Drop{}, err
 		}
+		after = tagged
 	}
 
-	return loadOne(ctx, q, user, d)
+	return after, nil
 }
 
 func Move(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, status Status, now time.Time) (Drop, error) {
-	d, err := q.DropMove(ctx, db.DropMoveParams{
-		UserID:  user.ID,
-		ID:      id,
-		Status:  status.Model(),
-		MovedAt: now,
-	})
-	if err != nil {
-		return Drop{}, err
+	// Snoozing needs a wake-up time.
+	if status == StatusSnoozed {
+		return Drop{}, api.ValidationError("status", status.String(), "use Snooze to snooze drops")
 	}
-	return loadOne(ctx, q, user, d)
-}
 
-func Delete(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Drop, error) {
-	// Load the tags we need to render the drop before deleting the drop_tags
-	// references.
-	tags, err := q.TagsDrop(ctx, db.TagsDropParams{
-		UserID: user.ID,
-		ID:     id,
-	})
+	before, err := Get(ctx, q, user, id)
 	if err != nil {
 		return Drop{}, err
 	}
 
-	// Delete all the drop tags by intersecting with the empty set. These
-	// references need to be removed before the drop can be deleted.
-	_, err = q.DropTagsIntersect(ctx, db.DropTagsIntersectParams{
-		DropID: id,
-		TagIds: []uuid.UUID{},
+	d, err := q.DropMove(ctx, db.DropMoveParams{
+		UserID:  user.ID,
+		ID:      id,
+		Status:  status.Model(),
+		MovedAt: now,
 	})
 	if err != nil {
 		return Drop{}, err
 	}
 
-	d, err := q.DropDelete(ctx, db.DropDeleteParams{
-		UserID: user.ID,
-		ID:     id,
-	})
+	after, err := loadOne(ctx, q, user, d)
 	if err != nil {
 		return Drop{}, err
 	}
-	return model(d, tags), nil
+	return after, recordEvent(ctx, q, user.ID, EventMove, &before, &after)
 }
 
 func Get(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Drop, error) {
@@ -180,19 +525,42 @@ func Get(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Drop
 	return loadOne(ctx, q, user, d)
 }
 
-func Next(ctx context.Context, q db.Queryable, user api.User) (Drop, error) {
-	d, err := q.DropNext(ctx, user.ID)
+// Next returns the oldest unread drop. Snoozed drops count as unread once
+// they wake up.
+func Next(ctx context.Context, q db.Queryable, user api.User, now time.Time) (Drop, error) {
+	d, err := q.DropNext(ctx, db.DropNextParams{
+		UserID: user.ID,
+		Now:    now,
+	})
 	if err != nil {
 		return Drop{}, err
 	}
 	return loadOne(ctx, q, user, d)
 }
 
-func List(ctx context.Context, q db.Queryable, user api.User, s Status, limit int32) ([]Drop, error) {
+// List lists drops with the given status, oldest first. If after is not nil,
+// the list starts after that position.
+//
+// Snoozed drops are listed as unread once they wake up, and as snoozed until
+// then.
+func List(ctx context.Context, q db.Queryable, user api.User, s Status, after *Cursor, limit int32, now time.Time) ([]Drop, error) {
+	unpinned, pinnedAt, movedAt, afterID := after.key()
+	statuses := []db.DropStatus{s.Model()}
+	awake := false
+	if s == StatusUnread {
+		statuses = append(statuses, db.DropStatusSnoozed)
+		awake = true
+	}
 	ds, err := q.DropList(ctx, db.DropListParams{
-		UserID:   user.ID,
-		Statuses: []db.DropStatus{s.Model()},
-		Limit:    limit,
+		UserID:        user.ID,
+		Statuses:      statuses,
+		Now:           now,
+		Awake:         awake,
+		Limit:         limit,
+		AfterUnpinned: unpinned,
+		AfterPinnedAt: pinnedAt,
+		AfterMovedAt:  movedAt,
+		AfterID:       afterID,
 	})
 	if err != nil {
 		return nil, err
@@ -200,52 +568,186 @@ func List(ctx context.Context, q db.Queryable, user api.User, s Status, limit in
 	return loadMany(ctx, q, user, ds)
 }
 
-func Search(ctx context.Context, q db.Queryable, user api.User, body ListBody) ([]Drop, error) {
+// ListPage lists a page of the drops matching the body, with a cursor for the
+// next page if there is one.
+func ListPage(ctx context.Context, q db.Queryable, user api.User, body ListBody, now time.Time) (ListResponse, error) {
+	limit := clampLimit(body.Limit)
+	// Fetch one extra drop to find out whether there's another page.
+	fetch := limit + 1
+	body.Limit = &fetch
+
+	// List uses a static query, so only build one with Filter when the body
+	// asks for more than a status.
+	var ds []Drop
+	var err error
+	if body.Status != StatusUnknown && body.Tags == nil && body.MaxReadingMinutes == nil && body.Query == "" {
+		ds, err = List(ctx, q, user, body.Status, body.Cursor, fetch, now)
+	} else {
+		ds, err = Filter(ctx, q, user, body, now)
+	}
+	if err != nil {
+		return ListResponse{}, err
+	}
+	return page(ds, limit), nil
+}
+
+// Filter lists drops matching the filters in the body. Like List, snoozed
+// drops that have woken up are treated as unread.
+func Filter(ctx context.Context, q db.Queryable, user api.User, body ListBody, now time.Time) ([]Drop, error) {
+	query, err := ParseQuery(body.Query)
+	if err != nil {
+		return nil, err
+	}
+
 	qq := db.Pq.
-		Select("drops").
-		Join("drop_tags ON drop_tags.drop_id = drops.id").
-		Join("tags ON tags.id = drop_tags.tag_id").
+		Select(db.DropColumns...).
+		From("drops").
 		Where(sq.Eq{
-			"drops.user_id": user.ID,
-			"tags.user_id":  user.ID,
+			"drops.user_id":    user.ID,
+			"drops.deleted_at": nil,
 		}).
+		OrderBy("drops.pinned_at asc nulls last", "drops.moved_at asc", "drops.id asc").
 		Limit(uint64(*body.Limit))
 
+	// Don't filter by status at all if it's unknown.
 	if body.Status != StatusUnknown {
-		qq = qq.Where(sq.Eq{"drops.status": body.Status})
+		qq = qq.Where(statusCond(body.Status, now))
 	}
 	if body.Tags != nil {
-		qq = qq.Where(sq.Eq{"tags.id": body.Tags})
+		qq = qq.Where(sq.Expr("drops.id IN (?)", taggedAny(user, *body.Tags)))
+	}
+	if m := body.MaxReadingMinutes; m != nil {
+		qq = qq.Where(sq.LtOrEq{"drops.reading_minutes": *m})
+	}
+	if !query.IsZero() {
+		qq = qq.Where(query.cond(user, now))
+	}
+	if body.Cursor != nil {
+		unpinned, pinnedAt, movedAt, id := body.Cursor.key()
+		qq = qq.Where(sq.Expr("(drops.pinned_at IS NULL, coalesce(drops.pinned_at, '0001-01-01'), drops.moved_at, drops.id) > (?, ?, ?, ?)", unpinned, pinnedAt, movedAt, id))
 	}
 
-	query, args, err := qq.ToSql()
+	stmt, args, err := qq.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := q.QueryContext(ctx, query, args...)
+	rows, err := q.QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	var drops []db.Drop
-	if err := scan.RowsStrict(&drops, rows); err != nil {
+	drops, err := db.ScanDrops(rows)
+	if err != nil {
 		return nil, err
 	}
 
 	return loadMany(ctx, q, user, drops)
 }
 
+// statusCond matches drops with the status. Snoozed drops that have woken up
+// count as unread.
+func statusCond(s Status, now time.Time) sq.Sqlizer {
+	switch s {
+	case StatusUnread:
+		return sq.Or{
+			sq.Eq{"drops.status": db.DropStatusUnread},
+			sq.And{
+				sq.Eq{"drops.status": db.DropStatusSnoozed},
+				sq.LtOrEq{"drops.snooze_until": now},
+			},
+		}
+	case StatusSnoozed:
+		return sq.And{
+			sq.Eq{"drops.status": db.DropStatusSnoozed},
+			sq.Gt{"drops.snooze_until": now},
+		}
+	default:
+		return sq.Eq{"drops.status": s.Model()}
+	}
+}
+
+type SearchResult struct {
+	Drop    Drop    `json:"drop"`
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// Search finds drops by full-text search over their titles and URLs. The
+// results are ordered by relevance, and each includes a snippet with the
+// matching terms highlighted.
+func Search(ctx context.Context, q db.Queryable, user api.User, query string, limit int32) ([]SearchResult, error) {
+	rows, err := q.DropSearch(ctx, db.DropSearchParams{
+		Query:      query,
+		UserID:     user.ID,
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ds := make([]db.Drop, 0, len(rows))
+	for _, r := range rows {
+		ds = append(ds, db.Drop{
+			ID:                r.ID,
+			UserID:            r.UserID,
+			Title:             r.Title,
+			URL:               r.URL,
+			Status:            r.Status,
+			MovedAt:           r.MovedAt,
+			CreatedAt:         r.CreatedAt,
+			UpdatedAt:         r.UpdatedAt,
+			SearchVector:      r.SearchVector,
+			Description:       r.Description,
+			SiteName:          r.SiteName,
+			ImageURL:          r.ImageURL,
+			MetadataFetchedAt: r.MetadataFetchedAt,
+			CanonicalURL:      r.CanonicalURL,
+			SnoozeUntil:       r.SnoozeUntil,
+			Notes:             r.Notes,
+			DeletedAt:         r.DeletedAt,
+			WordCount:         r.WordCount,
+			ReadingMinutes:    r.ReadingMinutes,
+			PinnedAt:          r.PinnedAt,
+		})
+	}
+	drops, err := loadMany(ctx, q, user, ds)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list of results should never be nil/null, so always make the slice.
+	res := make([]SearchResult, 0)
+	for i, r := range rows {
+		res = append(res, SearchResult{
+			Drop:    drops[i],
+			Rank:    r.Rank,
+			Snippet: r.Snippet,
+		})
+	}
+	return res, nil
+}
+
 func loadOne(ctx context.Context, q db.Queryable, user api.User, d db.Drop) (Drop, error) {
 	ts, err := q.TagsDrop(ctx, db.TagsDropParams{
 		UserID: user.ID,
 		ID:     d.ID,
 	})
-	return model(d, ts), err
+	if err != nil {
+		return Drop{}, err
+	}
+	hs, err := q.DropHighlightsList(ctx, db.DropHighlightsListParams{
+		UserID:  user.ID,
+		DropIds: []uuid.UUID{d.ID},
+	})
+	return model(d, ts, hs), err
 }
 
 func loadMany(ctx context.Context, q db.Queryable, user api.User, ds []db.Drop) ([]Drop, error) {
-	var dropIDs []uuid.UUID
+	dropIDs := make([]uuid.UUID, 0, len(ds))
+	for _, d := range ds {
+		dropIDs = append(dropIDs, d.ID)
+	}
 	tagRows, err := q.TagsDrops(ctx, db.TagsDropsParams{
 		UserID:  user.ID,
 		DropIds: dropIDs,
@@ -264,10 +766,22 @@ func loadMany(ctx context.Context, q db.Queryable, user api.User, ds []db.Drop)
 		})
 	}
 
+	hs, err := q.DropHighlightsList(ctx, db.DropHighlightsListParams{
+		UserID:  user.ID,
+		DropIds: dropIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	highlights := make(map[uuid.UUID][]db.DropHighlight)
+	for _, h := range hs {
+		highlights[h.DropID] = append(highlights[h.DropID], h)
+	}
+
 	// The list of drops should never be nil/null, so always make the slice.
 	res := make([]Drop, 0)
 	for _, d := range ds {
-		res = append(res, model(d, tags[d.ID]))
+		res = append(res, model(d, tags[d.ID], highlights[d.ID]))
 	}
 	return res, err
 }
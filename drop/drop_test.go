@@ -5,10 +5,12 @@ import (
 	"testing"
 	"time"
 
+	gofrs "github.com/gofrs/uuid"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/metagram-net/firehose/api"
 	"github.com/metagram-net/firehose/db"
 	"github.com/metagram-net/firehose/drop"
 	"github.com/metagram-net/firehose/internal/apitest"
@@ -25,7 +27,7 @@ func TestCreate(t *testing.T) {
 	title := "Example Dot Net"
 	url := "https://example.net"
 
-	d, err := drop.Create(ctx, q, user, title, url, nil, clock.Now())
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: title, URL: url}, clock.Now())
 	require.NoError(t, err)
 
 	assert.NoError(t, parseUUID(d.ID))
@@ -36,6 +38,152 @@ func TestCreate(t *testing.T) {
 	assert.Empty(t, d.Tags)
 }
 
+func TestCreateDuplicate(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: "https://example.net/a?utm_source=feed"}, clock.Now())
+	require.NoError(t, err)
+	d, err = drop.Move(ctx, q, user, gofrs.FromStringOrNil(d.ID), drop.StatusRead, clock.Now())
+	require.NoError(t, err)
+
+	_, err = drop.Create(ctx, q, user, nil, drop.CreateFields{URL: "https://EXAMPLE.net/a#comments"}, clock.Now())
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("duplicate_resource"), aerr.Code)
+	assert.Equal(t, &d, aerr.Details)
+
+	later := clock.Now().Add(time.Hour)
+	bumped, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: "https://example.net/a", Bump: true}, later)
+	require.NoError(t, err)
+	assert.Equal(t, d.ID, bumped.ID)
+	assert.Equal(t, drop.StatusUnread, bumped.Status)
+	assert.WithinDuration(t, later, bumped.MovedAt, 0)
+}
+
+func TestCreateTagNames(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	existing, err := q.TagCreate(ctx, db.TagCreateParams{UserID: user.ID, Name: "go"})
+	require.NoError(t, err)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: "https://example.net", TagNames: []string{"go", " reading ", "go"}}, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, d.Tags, 2)
+	names := map[string]string{}
+	for _, tg := range d.Tags {
+		names[tg.Name] = tg.ID
+	}
+	assert.Equal(t, existing.ID.String(), names["go"])
+	assert.Contains(t, names, "reading")
+
+	// Updating by name replaces the tags, reusing the one created above.
+	tags := []string{"reading"}
+	d, err = drop.Update(ctx, q, user, nil, gofrs.FromStringOrNil(d.ID), drop.UpdateFields{TagNames: &tags})
+	require.NoError(t, err)
+	require.Len(t, d.Tags, 1)
+	assert.Equal(t, names["reading"], d.Tags[0].ID)
+
+	// Batches reuse the same tags instead of creating them again.
+	ds, err := drop.CreateBatch(ctx, q, user, nil, []drop.BatchItem{
+		{URL: "https://example.org", Tags: []string{"go", "reading"}},
+	}, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, ds[0].Tags, 2)
+	for _, tg := range ds[0].Tags {
+		assert.Equal(t, names[tg.Name], tg.ID)
+	}
+}
+
+func TestUpdateForeignTag(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		other = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	theirs, err := q.TagCreate(ctx, db.TagCreateParams{UserID: other.ID, Name: "private"})
+	require.NoError(t, err)
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+
+	tags := []gofrs.UUID{theirs.ID}
+	_, err = drop.Update(ctx, q, user, nil, gofrs.FromStringOrNil(d.ID), drop.UpdateFields{Tags: &tags})
+	assert.ErrorIs(t, err, api.NoResourceError("tag", theirs.ID.String()))
+
+	d, err = drop.Get(ctx, q, user, gofrs.FromStringOrNil(d.ID))
+	require.NoError(t, err)
+	assert.Empty(t, d.Tags)
+}
+
+func TestSearch(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	_, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Routine Vacuuming", URL: "https://www.postgresql.org/docs/current/routine-vacuuming.html"}, clock.Now())
+	require.NoError(t, err)
+	_, err = drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Example Dot Net", URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+
+	rs, err := drop.Search(ctx, q, user, "postgresql vacuum", 20)
+	require.NoError(t, err)
+
+	require.Len(t, rs, 1)
+	assert.Equal(t, "Routine Vacuuming", rs[0].Drop.Title)
+	assert.Contains(t, rs[0].Snippet, "<b>Vacuuming</b>")
+}
+
+func TestSearchContent(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Example Dot Net", URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+	_, err = drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Example Dot Org", URL: "https://example.org"}, clock.Now())
+	require.NoError(t, err)
+
+	_, err = q.DropContentSave(ctx, db.DropContentSaveParams{
+		DropID:    gofrs.FromStringOrNil(d.ID),
+		UserID:    user.ID,
+		URL:       d.URL,
+		Title:     d.Title,
+		Text:      "Autovacuum keeps the tables tidy.",
+		FetchedAt: clock.Now(),
+	})
+	require.NoError(t, err)
+
+	rs, err := drop.Search(ctx, q, user, "autovacuum", 20)
+	require.NoError(t, err)
+
+	require.Len(t, rs, 1)
+	assert.Equal(t, d.ID, rs[0].Drop.ID)
+	assert.Contains(t, rs[0].Snippet, "<b>Autovacuum</b>")
+}
+
 func parseUUID(s string) error {
 	_, err := uuid.Parse(s)
 	if err != nil {
@@ -43,3 +191,54 @@ func parseUUID(s string) error {
 	}
 	return nil
 }
+
+func TestCreateBatchSnoozed(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	until := now.Add(24 * time.Hour)
+	movedAt := now.Add(-24 * time.Hour)
+	ds, err := drop.CreateBatch(ctx, q, user, nil, []drop.BatchItem{
+		{URL: "https://example.com/until", Status: drop.StatusSnoozed, MovedAt: &movedAt, SnoozeUntil: &until},
+		{URL: "https://example.com/forever", Status: drop.StatusSnoozed},
+	}, now)
+	require.NoError(t, err)
+	require.Len(t, ds, 2)
+
+	assert.Equal(t, drop.StatusSnoozed, ds[0].Status)
+	require.NotNil(t, ds[0].SnoozeUntil)
+	assert.WithinDuration(t, until, *ds[0].SnoozeUntil, 0)
+	// The wake-up time doesn't replace the original moved-at time.
+	assert.WithinDuration(t, movedAt, ds[0].MovedAt, 0)
+
+	// Nothing would ever wake it up, so it's unread instead.
+	assert.Equal(t, drop.StatusUnread, ds[1].Status)
+	assert.Nil(t, ds[1].SnoozeUntil)
+}
+
+func TestCreateBatchBodyValidate(t *testing.T) {
+	until := time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC)
+	err := drop.CreateBatchBody{Drops: []drop.BatchItem{
+		{URL: "https://example.com", Status: drop.StatusUnread, SnoozeUntil: &until},
+	}}.Validate()
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+
+	err = drop.CreateBatchBody{Drops: []drop.BatchItem{
+		{URL: "https://example.com", Status: drop.StatusSnoozed, SnoozeUntil: &until},
+	}}.Validate()
+	assert.NoError(t, err)
+
+	err = drop.CreateBatchBody{Drops: []drop.BatchItem{
+		{URL: "https://example.com", Highlights: []drop.BatchHighlight{{Quote: " "}}},
+	}}.Validate()
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+}
@@ -0,0 +1,121 @@
+package drop
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// ExpireBatchSize is the most drops Expire changes per policy at a time.
+const ExpireBatchSize = 100
+
+// ExpiryCutoff is the time a drop must have been moved before for the policy
+// to apply to it.
+func ExpiryCutoff(p db.ExpiryPolicy, now time.Time) time.Time {
+	return now.AddDate(0, 0, -int(p.MaxAgeDays))
+}
+
+// Expiring lists the drops that the policy would act on right now, oldest
+// first and at most limit of them, along with how many there are in total.
+// This doesn't change anything, so it's safe for dry runs.
+func Expiring(ctx context.Context, q db.Queryable, p db.ExpiryPolicy, limit int32, now time.Time) ([]Drop, int64, error) {
+	cutoff := ExpiryCutoff(p, now)
+	n, err := q.DropsExpiringCount(ctx, db.DropsExpiringCountParams{
+		UserID:      p.UserID,
+		Status:      p.Status,
+		MovedBefore: cutoff,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ds, err := q.DropsExpiring(ctx, db.DropsExpiringParams{
+		UserID:      p.UserID,
+		Status:      p.Status,
+		MovedBefore: cutoff,
+		MaxDrops:    limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := loadMany(ctx, q, api.User{ID: p.UserID}, ds)
+	return res, n, err
+}
+
+// Expire applies every user's expiry policies. Each policy changes at most
+// ExpireBatchSize drops at a time, and this returns true if any of them might
+// have more left.
+//
+// Policies with longer limits go first, so if a drop is old enough for
+// several policies, the one with the longest limit acts on it.
+func Expire(ctx context.Context, q db.Queryable, now time.Time) (bool, error) {
+	ps, err := q.ExpiryPoliciesAll(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	more := false
+	for _, p := range ps {
+		ds, err := q.DropsExpirable(ctx, db.DropsExpirableParams{
+			UserID:      p.UserID,
+			Status:      p.Status,
+			MovedBefore: ExpiryCutoff(p, now),
+			MaxDrops:    ExpireBatchSize,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, d := range ds {
+			if err := expire(ctx, q, p, d, now); err != nil {
+				return false, err
+			}
+		}
+		if len(ds) == ExpireBatchSize {
+			more = true
+		}
+	}
+	return more, nil
+}
+
+// expire applies the policy's action to one drop, recording the policy in the
+// drop's history.
+func expire(ctx context.Context, q db.Queryable, p db.ExpiryPolicy, d db.Drop, now time.Time) error {
+	user := api.User{ID: p.UserID}
+	policyID := uuid.NullUUID{UUID: p.ID, Valid: true}
+
+	before, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return err
+	}
+
+	switch p.Action {
+	case db.ExpiryActionDelete:
+		if _, err := q.DropTrash(ctx, db.DropTrashParams{
+			UserID:    user.ID,
+			ID:        d.ID,
+			DeletedAt: now,
+		}); err != nil {
+			return err
+		}
+		return recordPolicyEvent(ctx, q, user.ID, EventDelete, &before, nil, policyID)
+	default:
+		moved, err := q.DropMove(ctx, db.DropMoveParams{
+			UserID:  user.ID,
+			ID:      d.ID,
+			Status:  db.DropStatusExpired,
+			MovedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+		after, err := loadOne(ctx, q, user, moved)
+		if err != nil {
+			return err
+		}
+		return recordPolicyEvent(ctx, q, user.ID, EventMove, &before, &after, policyID)
+	}
+}
@@ -0,0 +1,95 @@
+package drop_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestExpire(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	create := func(url string, age time.Duration) uuid.UUID {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: url, URL: url}, now.Add(-age))
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(d.ID)
+	}
+	fresh := create("https://example.com/fresh", 10*24*time.Hour)
+	stale := create("https://example.com/stale", 70*24*time.Hour)
+	ancient := create("https://example.com/ancient", 400*24*time.Hour)
+
+	expirePolicy, err := q.ExpiryPolicyCreate(ctx, db.ExpiryPolicyCreateParams{
+		UserID:     user.ID,
+		Name:       "stale",
+		Status:     db.DropStatusUnread,
+		MaxAgeDays: 60,
+		Action:     db.ExpiryActionExpire,
+	})
+	require.NoError(t, err)
+	deletePolicy, err := q.ExpiryPolicyCreate(ctx, db.ExpiryPolicyCreateParams{
+		UserID:     user.ID,
+		Name:       "ancient",
+		Status:     db.DropStatusUnread,
+		MaxAgeDays: 365,
+		Action:     db.ExpiryActionDelete,
+	})
+	require.NoError(t, err)
+
+	// Previews count everything the policy applies to, without changing it.
+	ds, n, err := drop.Expiring(ctx, q, expirePolicy, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	require.Len(t, ds, 1)
+	assert.Equal(t, ancient.String(), ds[0].ID)
+
+	more, err := drop.Expire(ctx, q, now)
+	require.NoError(t, err)
+	assert.False(t, more)
+
+	d, err := drop.Get(ctx, q, user, fresh)
+	require.NoError(t, err)
+	assert.Equal(t, drop.StatusUnread, d.Status)
+
+	// The longer policy goes first, so the ancient drop is deleted instead of
+	// expired.
+	d, err = drop.Get(ctx, q, user, stale)
+	require.NoError(t, err)
+	assert.Equal(t, drop.StatusExpired, d.Status)
+	assert.Equal(t, now, d.MovedAt)
+
+	_, err = drop.Get(ctx, q, user, ancient)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// The history says which policy acted.
+	es, err := drop.History(ctx, q, user, stale)
+	require.NoError(t, err)
+	last := es[len(es)-1]
+	assert.Equal(t, drop.EventMove, last.Kind)
+	require.NotNil(t, last.PolicyID)
+	assert.Equal(t, expirePolicy.ID.String(), *last.PolicyID)
+
+	es, err = drop.History(ctx, q, user, ancient)
+	require.NoError(t, err)
+	last = es[len(es)-1]
+	assert.Equal(t, drop.EventDelete, last.Kind)
+	require.NotNil(t, last.PolicyID)
+	assert.Equal(t, deletePolicy.ID.String(), *last.PolicyID)
+
+	// Changes made by the user don't have a policy.
+	assert.Nil(t, es[0].PolicyID)
+}
@@ -0,0 +1,190 @@
+package drop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// ExportFormat identifies a Firehose export stream. The first line of every
+// export is an ExportHeader with this format and the version of the records
+// that follow.
+const ExportFormat = "firehose-export"
+
+// ExportVersion is the current version of the export records. Increment this
+// when changing ExportDrop in a way older importers can't read.
+//
+// Version 2 added the ExportTrailer.
+const ExportVersion = 2
+
+// ErrTruncatedExport means an export stream ended before its trailer, so some
+// of the drops are probably missing.
+var ErrTruncatedExport = errors.New("export is incomplete")
+
+type ExportHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// An ExportTrailer is the last line of an export. The server has already sent
+// a success status by the time it streams the drops, so this is how readers
+// know that the export finished and wasn't cut short by an error.
+type ExportTrailer struct {
+	End   bool `json:"end"`
+	Count int  `json:"count"`
+}
+
+// An ExportDrop is one line of an export. Tags are referenced by name so the
+// export can be imported into another account or server.
+type ExportDrop struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Status    Status    `json:"status"`
+	MovedAt   time.Time `json:"moved_at"`
+	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags"`
+	// SnoozeUntil is only set for snoozed drops, and PinnedAt for pinned ones.
+	SnoozeUntil *time.Time `json:"snooze_until,omitempty"`
+	PinnedAt    *time.Time `json:"pinned_at,omitempty"`
+
+	Notes      string            `json:"notes,omitempty"`
+	Highlights []ExportHighlight `json:"highlights,omitempty"`
+}
+
+type ExportHighlight struct {
+	Quote     string    `json:"quote"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Export streams all of a user's drops as JSON Lines: a header, then one
+// ExportDrop per line.
+type Export struct {
+	ctx  context.Context
+	q    db.Queryable
+	user api.User
+	now  time.Time
+}
+
+func NewExport(ctx context.Context, q db.Queryable, user api.User, now time.Time) Export {
+	return Export{ctx, q, user, now}
+}
+
+func (Export) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (e Export) Stream(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	err := enc.Encode(ExportHeader{
+		Format:     ExportFormat,
+		Version:    ExportVersion,
+		ExportedAt: e.now,
+	})
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = db.DropsExport(e.ctx, e.q, e.user.ID, func(r db.DropExportRow) error {
+		count++
+		var hs []ExportHighlight
+		for _, h := range r.Highlights {
+			hs = append(hs, ExportHighlight{
+				Quote:     h.Quote,
+				Comment:   h.Comment.String,
+				CreatedAt: h.CreatedAt,
+			})
+		}
+		var snoozeUntil *time.Time
+		if r.Status == db.DropStatusSnoozed {
+			snoozeUntil = nullTime(r.SnoozeUntil)
+		}
+		return enc.Encode(ExportDrop{
+			ID:          r.ID.String(),
+			Title:       r.Title.String,
+			URL:         r.URL,
+			Status:      StatusModel(r.Status),
+			MovedAt:     r.MovedAt,
+			CreatedAt:   r.CreatedAt,
+			Tags:        r.Tags,
+			SnoozeUntil: snoozeUntil,
+			PinnedAt:    nullTime(r.PinnedAt),
+			Notes:       r.Notes.String,
+			Highlights:  hs,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return enc.Encode(ExportTrailer{End: true, Count: count})
+}
+
+// An ExportDecoder reads the drops in an export stream.
+type ExportDecoder struct {
+	dec    *json.Decoder
+	header ExportHeader
+	count  int
+	done   bool
+}
+
+// NewExportDecoder reads the header of an export stream. Checking that the
+// format and version are supported is up to the caller.
+func NewExportDecoder(r io.Reader) (*ExportDecoder, error) {
+	d := &ExportDecoder{dec: json.NewDecoder(r)}
+	if err := d.dec.Decode(&d.header); err != nil {
+		return nil, fmt.Errorf("read export header: %w", err)
+	}
+	return d, nil
+}
+
+func (d *ExportDecoder) Header() ExportHeader {
+	return d.header
+}
+
+// Next returns the next drop, or io.EOF after the last one. Since version 2,
+// exports have to end with a trailer that counts the drops, and this returns
+// ErrTruncatedExport if it's missing or the count is wrong.
+func (d *ExportDecoder) Next() (ExportDrop, error) {
+	var line json.RawMessage
+	err := d.dec.Decode(&line)
+	if errors.Is(err, io.EOF) {
+		if !d.done && d.header.Version >= 2 {
+			return ExportDrop{}, fmt.Errorf("%w: no trailer after %d drops", ErrTruncatedExport, d.count)
+		}
+		return ExportDrop{}, io.EOF
+	}
+	if err != nil {
+		return ExportDrop{}, fmt.Errorf("read export drop: %w", err)
+	}
+	if d.done {
+		return ExportDrop{}, errors.New("read export drop: found data after the trailer")
+	}
+
+	var t ExportTrailer
+	if err := json.Unmarshal(line, &t); err != nil {
+		return ExportDrop{}, fmt.Errorf("read export drop: %w", err)
+	}
+	if t.End {
+		if t.Count != d.count {
+			return ExportDrop{}, fmt.Errorf("%w: trailer counts %d drops, but there were %d", ErrTruncatedExport, t.Count, d.count)
+		}
+		d.done = true
+		return d.Next()
+	}
+
+	var e ExportDrop
+	if err := json.Unmarshal(line, &e); err != nil {
+		return ExportDrop{}, fmt.Errorf("read export drop: %w", err)
+	}
+	d.count++
+	return e, nil
+}
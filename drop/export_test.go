@@ -0,0 +1,126 @@
+package drop_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/importer"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestExport(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	pinned, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Pinned", URL: "https://example.com/pinned"}, now.Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = drop.Pin(ctx, q, user, uuid.FromStringOrNil(pinned.ID), now)
+	require.NoError(t, err)
+	plain, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Plain", URL: "https://example.com/plain"}, now)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, drop.NewExport(ctx, q, user, now).Stream(&b))
+
+	dec, err := drop.NewExportDecoder(&b)
+	require.NoError(t, err)
+	assert.Equal(t, drop.ExportFormat, dec.Header().Format)
+
+	var ids []string
+	for {
+		d, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+	assert.Equal(t, []string{pinned.ID, plain.ID}, ids)
+}
+
+func TestExportImport(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		other = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+	items := []drop.BatchItem{{
+		Title:     "Annotated",
+		URL:       "https://example.com/annotated",
+		Status:    drop.StatusUnread,
+		MovedAt:   at(48 * time.Hour),
+		CreatedAt: at(72 * time.Hour),
+		PinnedAt:  at(time.Hour),
+		Tags:      []string{"go"},
+		Notes:     "Worth a second read.",
+		Highlights: []drop.BatchHighlight{
+			{Quote: "First", Comment: "Good point", CreatedAt: at(3 * time.Hour)},
+			{Quote: "Second", CreatedAt: at(2 * time.Hour)},
+			{Quote: "Third"},
+		},
+	}}
+	_, err := drop.CreateBatch(ctx, q, user, nil, items, now)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, drop.NewExport(ctx, q, user, now).Stream(&b))
+	got, err := importer.ParseFirehose(&b)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	want := items[0]
+	// The third highlight didn't have a time, so it got one on import.
+	want.Highlights[2].CreatedAt = &now
+	assert.Equal(t, want.Notes, got[0].Notes)
+	for _, ts := range [][2]*time.Time{
+		{want.MovedAt, got[0].MovedAt},
+		{want.CreatedAt, got[0].CreatedAt},
+		{want.PinnedAt, got[0].PinnedAt},
+	} {
+		require.NotNil(t, ts[1])
+		assert.WithinDuration(t, *ts[0], *ts[1], 0)
+	}
+	require.Len(t, got[0].Highlights, 3)
+	for i, h := range got[0].Highlights {
+		assert.Equal(t, want.Highlights[i].Quote, h.Quote)
+		assert.Equal(t, want.Highlights[i].Comment, h.Comment)
+		require.NotNil(t, h.CreatedAt)
+		assert.WithinDuration(t, *want.Highlights[i].CreatedAt, *h.CreatedAt, 0)
+	}
+
+	ds, err := drop.CreateBatch(ctx, q, other, nil, got, now)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	assert.Equal(t, "Worth a second read.", ds[0].Notes)
+	require.NotNil(t, ds[0].PinnedAt)
+	assert.WithinDuration(t, *want.PinnedAt, *ds[0].PinnedAt, 0)
+	require.Len(t, ds[0].Highlights, 3)
+	for i, h := range ds[0].Highlights {
+		assert.Equal(t, want.Highlights[i].Quote, h.Quote)
+		assert.WithinDuration(t, *want.Highlights[i].CreatedAt, h.CreatedAt, 0)
+	}
+}
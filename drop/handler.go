@@ -1,9 +1,14 @@
 package drop
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/canonical"
 	"github.com/metagram-net/firehose/db"
 )
 
@@ -19,11 +24,83 @@ func (id ID) UUID() uuid.UUID {
 	return uuid.UUID(id)
 }
 
-type Handler struct{}
+type Handler struct {
+	// URLs are the extra rules for canonicalizing drop URLs.
+	URLs canonical.Rules
+}
+
+// NextBody narrows down the drops NextMatching picks from. Drops must match at
+// least one entry in each include list and none in the exclude lists. Domains
+// match their subdomains too.
+//
+// With no statuses given at all, only unread drops are included.
+type NextBody struct {
+	Tags            []uuid.UUID `json:"tags,omitempty"`
+	ExcludeTags     []uuid.UUID `json:"exclude_tags,omitempty"`
+	Domains         []string    `json:"domains,omitempty"`
+	ExcludeDomains  []string    `json:"exclude_domains,omitempty"`
+	Statuses        []Status    `json:"statuses,omitempty"`
+	ExcludeStatuses []Status    `json:"exclude_statuses,omitempty"`
+	Strategy        Strategy    `json:"strategy,omitempty"`
+	// MaxReadingMinutes only picks drops with a reading time estimate of at
+	// most this many minutes. Drops without an estimate are skipped.
+	MaxReadingMinutes *int32 `json:"max_reading_minutes,omitempty"`
+	// Query only picks drops that match it. See Query for the syntax. If it
+	// filters on status, unread isn't the default anymore.
+	Query string `json:"query,omitempty"`
+}
+
+func (b NextBody) Validate() error {
+	if err := validateMaxReadingMinutes(b.MaxReadingMinutes); err != nil {
+		return err
+	}
+	if _, err := ParseQuery(b.Query); err != nil {
+		return err
+	}
+	if b.Strategy != "" && !b.Strategy.valid() {
+		return api.ValidationError("strategy", b.Strategy, fmt.Sprintf("must be one of: %s", strings.Join(StrategyValueStrings(), ", ")))
+	}
+	for i, d := range b.Domains {
+		if normalizeDomain(d) == "" {
+			return api.ValidationError(fmt.Sprintf("domains[%d]", i), d, "must not be blank")
+		}
+	}
+	for i, d := range b.ExcludeDomains {
+		if normalizeDomain(d) == "" {
+			return api.ValidationError(fmt.Sprintf("exclude_domains[%d]", i), d, "must not be blank")
+		}
+	}
+	for i, s := range b.Statuses {
+		if s == StatusUnknown {
+			return api.ValidationError(fmt.Sprintf("statuses[%d]", i), s, "must be a known status")
+		}
+	}
+	for i, s := range b.ExcludeStatuses {
+		if s == StatusUnknown {
+			return api.ValidationError(fmt.Sprintf("exclude_statuses[%d]", i), s, "must be a known status")
+		}
+	}
+	return nil
+}
+
+// filtered reports whether the body asks for anything but the oldest unread
+// drop.
+func (b NextBody) filtered() bool {
+	return len(b.Tags) > 0 || len(b.ExcludeTags) > 0 ||
+		len(b.Domains) > 0 || len(b.ExcludeDomains) > 0 ||
+		len(b.Statuses) > 0 || len(b.ExcludeStatuses) > 0 ||
+		b.MaxReadingMinutes != nil || b.Query != "" ||
+		(b.Strategy != "" && b.Strategy != StrategyOldest)
+}
 
 func (Handler) Next(ctx api.Context, user api.User) (Drop, error) {
 	q := db.New(ctx.Tx)
-	return Next(ctx, q, user)
+	return Next(ctx, q, user, ctx.Clock.Now())
+}
+
+func (Handler) NextMatching(ctx api.Context, user api.User, body NextBody) (Drop, error) {
+	q := db.New(ctx.Tx)
+	return NextMatching(ctx, q, user, body, ctx.Clock.Now())
 }
 
 type GetParams struct {
@@ -39,64 +116,200 @@ type ListBody struct {
 	Status Status       `json:"status,omitempty"`
 	Limit  *int32       `json:"limit,omitempty"`
 	Tags   *[]uuid.UUID `json:"tags"`
+	Cursor *Cursor      `json:"cursor,omitempty"`
+	// MaxReadingMinutes only lists drops with a reading time estimate of at
+	// most this many minutes. Drops without an estimate are skipped.
+	MaxReadingMinutes *int32 `json:"max_reading_minutes,omitempty"`
+	// Query only lists drops that match it. See Query for the syntax. It's
+	// combined with the other filters, so leave the status unset to filter
+	// on status in the query.
+	Query string `json:"query,omitempty"`
+}
+
+func (b ListBody) Validate() error {
+	if err := validateMaxReadingMinutes(b.MaxReadingMinutes); err != nil {
+		return err
+	}
+	_, err := ParseQuery(b.Query)
+	return err
+}
+
+func validateMaxReadingMinutes(m *int32) error {
+	if m != nil && *m <= 0 {
+		return api.ValidationError("max_reading_minutes", fmt.Sprint(*m), "must be positive")
+	}
+	return nil
 }
 
 type ListResponse struct {
-	Drops []Drop `json:"drops"`
+	Drops      []Drop  `json:"drops"`
+	NextCursor *Cursor `json:"next_cursor,omitempty"`
 }
 
 func (Handler) List(ctx api.Context, u api.User, body ListBody) (ListResponse, error) {
-	// The point of Firehose is to force me to consume or delete the oldest
-	// content first. Listing is useful if some content is not currently
-	// consumable (videos and PDFs are the usual examples) so you have to
-	// "scroll past" a few drops. But the point is to avoid scrolling for a
-	// long time to find something, so don't allow large limits here.
-	limit := int32(20)
-	l := body.Limit
+	q := db.New(ctx.Tx)
+	return ListPage(ctx, q, u, body, ctx.Clock.Now())
+}
+
+// page trims the list of drops to the limit. If that removed anything, the
+// response includes a cursor to fetch the next page.
+func page(ds []Drop, limit int32) ListResponse {
+	if int32(len(ds)) <= limit {
+		return ListResponse{Drops: ds}
+	}
+	ds = ds[:limit]
+	return ListResponse{
+		Drops:      ds,
+		NextCursor: cursorAfter(ds[len(ds)-1]),
+	}
+}
+
+// The point of Firehose is to force me to consume or delete the oldest content
+// first. Listing is useful if some content is not currently consumable (videos
+// and PDFs are the usual examples) so you have to "scroll past" a few drops.
+// But the point is to avoid scrolling for a long time to find something, so
+// don't allow large limits here.
+const maxLimit = int32(20)
+
+func clampLimit(l *int32) int32 {
+	limit := maxLimit
 	if l != nil && 0 < *l && *l < limit {
 		limit = *l
 	}
-	body.Limit = &limit
+	return limit
+}
 
-	q := db.New(ctx.Tx)
+type SearchBody struct {
+	Query string `json:"query,omitempty"`
+	Limit *int32 `json:"limit,omitempty"`
+}
 
-	// The query is simpler (and probably faster?) if it doesn't have to join
-	// tables to filter on tags.
-	if body.Tags == nil {
-		ds, err := List(ctx, q, u, body.Status, limit)
-		return ListResponse{Drops: ds}, err
+func (b SearchBody) Validate() error {
+	if strings.TrimSpace(b.Query) == "" {
+		return api.ValidationError("query", b.Query, "must not be blank")
 	}
+	return nil
+}
+
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+}
+
+func (Handler) Search(ctx api.Context, u api.User, body SearchBody) (SearchResponse, error) {
+	q := db.New(ctx.Tx)
+	rs, err := Search(ctx, q, u, body.Query, clampLimit(body.Limit))
+	return SearchResponse{Results: rs}, err
+}
+
+type HistoryBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
 
-	// TODO: Should search be its own endpoint?
-	ds, err := Search(ctx, q, u, body)
-	return ListResponse{Drops: ds}, err
+type HistoryResponse struct {
+	Events []Event `json:"events"`
+}
+
+func (Handler) History(ctx api.Context, u api.User, body HistoryBody) (HistoryResponse, error) {
+	q := db.New(ctx.Tx)
+	es, err := History(ctx, q, u, body.ID)
+	return HistoryResponse{Events: es}, err
+}
+
+type ContentBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Content(ctx api.Context, u api.User, body ContentBody) (Content, error) {
+	q := db.New(ctx.Tx)
+	return GetContent(ctx, q, u, body.ID)
+}
+
+//nolint:unparam // The always-nil error is intentional.
+func (Handler) Export(ctx api.Context, u api.User) (Export, error) {
+	q := db.New(ctx.Tx)
+	return NewExport(ctx, q, u, ctx.Clock.Now()), nil
 }
 
 type CreateBody struct {
 	Title  string      `json:"title,omitempty"`
 	URL    string      `json:"url,omitempty"`
 	TagIDs []uuid.UUID `json:"tag_ids,omitempty"`
+	// TagNames are more tags to add, by name. Any that don't exist yet are
+	// created.
+	TagNames []string `json:"tag_names,omitempty"`
+	Bump     bool     `json:"bump,omitempty"`
 }
 
-func (Handler) Create(ctx api.Context, u api.User, body CreateBody) (Drop, error) {
+func (h Handler) Create(ctx api.Context, u api.User, body CreateBody) (Drop, error) {
 	q := db.New(ctx.Tx)
 	now := ctx.Clock.Now()
-	return Create(ctx, q, u, body.Title, body.URL, body.TagIDs, now)
+	return Create(ctx, q, u, h.URLs, CreateFields{
+		Title:    body.Title,
+		URL:      body.URL,
+		TagIDs:   body.TagIDs,
+		TagNames: body.TagNames,
+		Bump:     body.Bump,
+	}, now)
+}
+
+// MaxBatchSize is the largest number of drops that can be created in one
+// batch. Clients with more than this should split them across requests.
+const MaxBatchSize = 1000
+
+type CreateBatchBody struct {
+	Drops []BatchItem `json:"drops"`
+}
+
+func (b CreateBatchBody) Validate() error {
+	if len(b.Drops) > MaxBatchSize {
+		return api.ValidationError("drops", fmt.Sprint(len(b.Drops)), fmt.Sprintf("must not have more than %d items", MaxBatchSize))
+	}
+	for i, d := range b.Drops {
+		if strings.TrimSpace(d.URL) == "" {
+			return api.ValidationError(fmt.Sprintf("drops[%d].url", i), d.URL, "must not be blank")
+		}
+		if d.SnoozeUntil != nil && d.Status != StatusSnoozed {
+			return api.ValidationError(fmt.Sprintf("drops[%d].snooze_until", i), d.SnoozeUntil.Format(time.RFC3339), "must only be set for snoozed drops")
+		}
+		for j, h := range d.Highlights {
+			if strings.TrimSpace(h.Quote) == "" {
+				return api.ValidationError(fmt.Sprintf("drops[%d].highlights[%d].quote", i, j), h.Quote, "must not be blank")
+			}
+		}
+	}
+	return nil
+}
+
+type CreateBatchResponse struct {
+	Drops []Drop `json:"drops"`
+}
+
+func (h Handler) CreateBatch(ctx api.Context, u api.User, body CreateBatchBody) (CreateBatchResponse, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+	ds, err := CreateBatch(ctx, q, u, h.URLs, body.Drops, now)
+	return CreateBatchResponse{Drops: ds}, err
 }
 
 type UpdateBody struct {
-	ID    uuid.UUID    `json:"id,omitempty"`
-	Title *string      `json:"title,omitempty"`
-	URL   *string      `json:"url,omitempty"`
-	Tags  *[]uuid.UUID `json:"tags,omitempty"`
+	ID    uuid.UUID `json:"id,omitempty"`
+	Title *string   `json:"title,omitempty"`
+	URL   *string   `json:"url,omitempty"`
+	// Tags and TagNames together replace the drop's tags. Any tags named in
+	// TagNames that don't exist yet are created.
+	Tags     *[]uuid.UUID `json:"tags,omitempty"`
+	TagNames *[]string    `json:"tag_names,omitempty"`
+	Notes    *string      `json:"notes,omitempty"`
 }
 
-func (Handler) Update(ctx api.Context, u api.User, body UpdateBody) (Drop, error) {
+func (h Handler) Update(ctx api.Context, u api.User, body UpdateBody) (Drop, error) {
 	q := db.New(ctx.Tx)
-	return Update(ctx, q, u, body.ID, UpdateFields{
-		Title: body.Title,
-		URL:   body.URL,
-		Tags:  body.Tags,
+	return Update(ctx, q, u, h.URLs, body.ID, UpdateFields{
+		Title:    body.Title,
+		URL:      body.URL,
+		Tags:     body.Tags,
+		TagNames: body.TagNames,
+		Notes:    body.Notes,
 	})
 }
 
@@ -111,11 +324,192 @@ func (Handler) Move(ctx api.Context, u api.User, body MoveBody) (Drop, error) {
 	return Move(ctx, q, u, body.ID, body.Status, now)
 }
 
+type PinBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Pin(ctx api.Context, u api.User, body PinBody) (Drop, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+	return Pin(ctx, q, u, body.ID, now)
+}
+
+type UnpinBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Unpin(ctx api.Context, u api.User, body UnpinBody) (Drop, error) {
+	q := db.New(ctx.Tx)
+	return Unpin(ctx, q, u, body.ID)
+}
+
 type DeleteBody struct {
 	ID uuid.UUID `json:"id,omitempty"`
 }
 
 func (Handler) Delete(ctx api.Context, u api.User, body DeleteBody) (Drop, error) {
 	q := db.New(ctx.Tx)
-	return Delete(ctx, q, u, body.ID)
+	now := ctx.Clock.Now()
+	return Delete(ctx, q, u, body.ID, now)
+}
+
+type TrashResponse struct {
+	Drops []Drop `json:"drops"`
+}
+
+func (Handler) Trash(ctx api.Context, u api.User) (TrashResponse, error) {
+	q := db.New(ctx.Tx)
+	ds, err := Trash(ctx, q, u)
+	return TrashResponse{Drops: ds}, err
+}
+
+type RestoreBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Restore(ctx api.Context, u api.User, body RestoreBody) (Drop, error) {
+	q := db.New(ctx.Tx)
+	return Restore(ctx, q, u, body.ID)
+}
+
+type EmptyTrashBody struct{}
+
+type EmptyTrashResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+func (Handler) EmptyTrash(ctx api.Context, u api.User, body EmptyTrashBody) (EmptyTrashResponse, error) {
+	q := db.New(ctx.Tx)
+	n, err := EmptyTrash(ctx, q, u)
+	return EmptyTrashResponse{Deleted: n}, err
+}
+
+type BulkResponse struct {
+	Results []BulkResult `json:"results"`
+}
+
+type BulkMoveBody struct {
+	Selection
+	Status Status `json:"status,omitempty"`
+}
+
+func (b BulkMoveBody) Validate() error {
+	if err := b.Selection.Validate(); err != nil {
+		return err
+	}
+	if b.Status == StatusUnknown || b.Status == StatusSnoozed {
+		return api.ValidationError("status", b.Status, fmt.Sprintf("must be one of: %s", strings.Join(MoveStatusValueStrings(), ", ")))
+	}
+	return nil
+}
+
+func (Handler) BulkMove(ctx api.Context, u api.User, body BulkMoveBody) (BulkResponse, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+	rs, err := BulkMove(ctx, q, u, body.Selection, body.Status, now)
+	return BulkResponse{Results: rs}, err
+}
+
+type BulkTagBody struct {
+	Selection
+	AddTags    []uuid.UUID `json:"add_tags,omitempty"`
+	RemoveTags []uuid.UUID `json:"remove_tags,omitempty"`
+}
+
+func (b BulkTagBody) Validate() error {
+	if err := b.Selection.Validate(); err != nil {
+		return err
+	}
+	if len(b.AddTags) == 0 && len(b.RemoveTags) == 0 {
+		return api.ValidationError("add_tags", "", "must be set if there are no remove_tags")
+	}
+	for i, r := range b.RemoveTags {
+		for _, a := range b.AddTags {
+			if r == a {
+				return api.ValidationError(fmt.Sprintf("remove_tags[%d]", i), r, "must not also be in add_tags")
+			}
+		}
+	}
+	return nil
+}
+
+func (Handler) BulkTag(ctx api.Context, u api.User, body BulkTagBody) (BulkResponse, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+	rs, err := BulkTag(ctx, q, u, body.Selection, body.AddTags, body.RemoveTags, now)
+	return BulkResponse{Results: rs}, err
+}
+
+type BulkDeleteBody struct {
+	Selection
+}
+
+func (Handler) BulkDelete(ctx api.Context, u api.User, body BulkDeleteBody) (BulkResponse, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+	rs, err := BulkDelete(ctx, q, u, body.Selection, now)
+	return BulkResponse{Results: rs}, err
+}
+
+type SnoozeBody struct {
+	ID    uuid.UUID `json:"id,omitempty"`
+	Until time.Time `json:"until,omitempty"`
+}
+
+func (b SnoozeBody) Validate() error {
+	if b.Until.IsZero() {
+		return api.ValidationError("until", "", "must be set")
+	}
+	return nil
+}
+
+func (Handler) Snooze(ctx api.Context, u api.User, body SnoozeBody) (Drop, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+	return Snooze(ctx, q, u, body.ID, body.Until, now)
+}
+
+type AddHighlightBody struct {
+	DropID  uuid.UUID `json:"drop_id,omitempty"`
+	Quote   string    `json:"quote,omitempty"`
+	Comment string    `json:"comment,omitempty"`
+}
+
+func (b AddHighlightBody) Validate() error {
+	if strings.TrimSpace(b.Quote) == "" {
+		return api.ValidationError("quote", b.Quote, "must not be blank")
+	}
+	return nil
+}
+
+func (Handler) AddHighlight(ctx api.Context, u api.User, body AddHighlightBody) (Highlight, error) {
+	q := db.New(ctx.Tx)
+	return AddHighlight(ctx, q, u, body.DropID, body.Quote, body.Comment)
+}
+
+type EditHighlightBody struct {
+	ID      uuid.UUID `json:"id,omitempty"`
+	Quote   *string   `json:"quote,omitempty"`
+	Comment *string   `json:"comment,omitempty"`
+}
+
+func (b EditHighlightBody) Validate() error {
+	if b.Quote != nil && strings.TrimSpace(*b.Quote) == "" {
+		return api.ValidationError("quote", *b.Quote, "must not be blank")
+	}
+	return nil
+}
+
+func (Handler) EditHighlight(ctx api.Context, u api.User, body EditHighlightBody) (Highlight, error) {
+	q := db.New(ctx.Tx)
+	return EditHighlight(ctx, q, u, body.ID, body.Quote, body.Comment)
+}
+
+type RemoveHighlightBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) RemoveHighlight(ctx api.Context, u api.User, body RemoveHighlightBody) (Highlight, error) {
+	q := db.New(ctx.Tx)
+	return RemoveHighlight(ctx, q, u, body.ID)
 }
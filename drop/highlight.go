@@ -0,0 +1,96 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// A Highlight is a passage quoted from a drop's page, with an optional comment
+// about it.
+type Highlight struct {
+	ID        string    `json:"id"`
+	DropID    string    `json:"drop_id"`
+	Quote     string    `json:"quote"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func highlightModel(h db.DropHighlight) Highlight {
+	return Highlight{
+		ID:        h.ID.String(),
+		DropID:    h.DropID.String(),
+		Quote:     h.Quote,
+		Comment:   h.Comment.String,
+		CreatedAt: h.CreatedAt,
+	}
+}
+
+func AddHighlight(ctx context.Context, q db.Queryable, user api.User, dropID uuid.UUID, quote, comment string) (Highlight, error) {
+	// Check the drop belongs to the user, since the foreign key won't.
+	_, err := q.DropFind(ctx, db.DropFindParams{
+		UserID: user.ID,
+		ID:     dropID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Highlight{}, api.NoResourceError("drop", dropID.String())
+	}
+	if err != nil {
+		return Highlight{}, err
+	}
+
+	h, err := q.DropHighlightCreate(ctx, db.DropHighlightCreateParams{
+		UserID:  user.ID,
+		DropID:  dropID,
+		Quote:   quote,
+		Comment: nullString(comment),
+	})
+	return highlightModel(h), err
+}
+
+// EditHighlight changes the fields of a highlight that are set. Set comment to
+// the empty string to remove it.
+func EditHighlight(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, quote, comment *string) (Highlight, error) {
+	h, err := q.DropHighlightFind(ctx, db.DropHighlightFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Highlight{}, api.NoResourceError("highlight", id.String())
+	}
+	if err != nil {
+		return Highlight{}, err
+	}
+
+	params := db.DropHighlightUpdateParams{
+		UserID:  user.ID,
+		ID:      id,
+		Quote:   h.Quote,
+		Comment: h.Comment,
+	}
+	if quote != nil {
+		params.Quote = *quote
+	}
+	if comment != nil {
+		params.Comment = nullString(*comment)
+	}
+	h, err = q.DropHighlightUpdate(ctx, params)
+	return highlightModel(h), err
+}
+
+func RemoveHighlight(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Highlight, error) {
+	h, err := q.DropHighlightDelete(ctx, db.DropHighlightDeleteParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Highlight{}, api.NoResourceError("highlight", id.String())
+	}
+	return highlightModel(h), err
+}
@@ -0,0 +1,63 @@
+package drop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestNotesAndHighlights(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Paper", URL: "https://example.net/paper"}, clock.Now())
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(d.ID)
+	assert.Empty(t, d.Notes)
+	assert.Empty(t, d.Highlights)
+
+	notes := "Read section 3 again."
+	d, err = drop.Update(ctx, q, user, nil, id, drop.UpdateFields{Notes: &notes})
+	require.NoError(t, err)
+	assert.Equal(t, notes, d.Notes)
+
+	h, err := drop.AddHighlight(ctx, q, user, id, "The key insight", "")
+	require.NoError(t, err)
+	assert.Equal(t, "The key insight", h.Quote)
+	assert.Empty(t, h.Comment)
+
+	comment := "Not so sure"
+	h, err = drop.EditHighlight(ctx, q, user, uuid.FromStringOrNil(h.ID), nil, &comment)
+	require.NoError(t, err)
+	assert.Equal(t, "The key insight", h.Quote)
+	assert.Equal(t, comment, h.Comment)
+
+	d, err = drop.Get(ctx, q, user, id)
+	require.NoError(t, err)
+	require.Len(t, d.Highlights, 1)
+	assert.Equal(t, h, d.Highlights[0])
+
+	_, err = drop.RemoveHighlight(ctx, q, user, uuid.FromStringOrNil(h.ID))
+	require.NoError(t, err)
+	d, err = drop.Get(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Empty(t, d.Highlights)
+
+	// Other users can't highlight the drop.
+	other := apitest.User(t, ctx, tx)
+	_, err = drop.AddHighlight(ctx, q, other, id, "Mine now", "")
+	assert.ErrorIs(t, err, api.NoResourceError("drop", id.String()))
+}
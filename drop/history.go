@@ -0,0 +1,122 @@
+package drop
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+type EventKind string
+
+const (
+	EventCreate  EventKind = "create"
+	EventUpdate  EventKind = "update"
+	EventTag     EventKind = "tag"
+	EventMove    EventKind = "move"
+	EventDelete  EventKind = "delete"
+	EventRestore EventKind = "restore"
+)
+
+// An Event records one change to a drop. Before and After are snapshots of
+// the drop. Before is null for creates and restores, and After is null for
+// deletes. PolicyID is set if an expiry policy made the change instead of the
+// user.
+type Event struct {
+	ID        string    `json:"id"`
+	DropID    string    `json:"drop_id"`
+	Kind      EventKind `json:"kind"`
+	Before    *Drop     `json:"before"`
+	After     *Drop     `json:"after"`
+	PolicyID  *string   `json:"policy_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func eventModel(e db.DropEvent) (Event, error) {
+	var before, after *Drop
+	if err := json.Unmarshal(e.Before, &before); err != nil {
+		return Event{}, err
+	}
+	if err := json.Unmarshal(e.After, &after); err != nil {
+		return Event{}, err
+	}
+	var policyID *string
+	if e.PolicyID.Valid {
+		id := e.PolicyID.UUID.String()
+		policyID = &id
+	}
+	return Event{
+		ID:        e.ID.String(),
+		DropID:    e.DropID.String(),
+		Kind:      EventKind(e.Kind),
+		Before:    before,
+		After:     after,
+		PolicyID:  policyID,
+		CreatedAt: e.CreatedAt,
+	}, nil
+}
+
+// recordEvent saves a snapshot of the drop before and after a change. Call
+// this with the same transaction as the change itself.
+func recordEvent(ctx context.Context, q db.Queryable, userID uuid.UUID, kind EventKind, before, after *Drop) error {
+	return recordPolicyEvent(ctx, q, userID, kind, before, after, uuid.NullUUID{})
+}
+
+// recordPolicyEvent is recordEvent for changes made by an expiry policy.
+func recordPolicyEvent(ctx context.Context, q db.Queryable, userID uuid.UUID, kind EventKind, before, after *Drop, policyID uuid.NullUUID) error {
+	var id string
+	if before != nil {
+		id = before.ID
+	} else {
+		id = after.ID
+	}
+	dropID, err := uuid.FromString(id)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	a, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.DropEventCreate(ctx, db.DropEventCreateParams{
+		UserID:   userID,
+		DropID:   dropID,
+		Kind:     db.DropEventKind(kind),
+		Before:   b,
+		After:    a,
+		PolicyID: policyID,
+	})
+	return err
+}
+
+// History lists the changes to a drop, oldest first. Deleting a drop keeps its
+// history, so this works for deleted drops too.
+func History(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) ([]Event, error) {
+	es, err := q.DropEventList(ctx, db.DropEventListParams{
+		UserID: user.ID,
+		DropID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Event, 0, len(es))
+	for _, e := range es {
+		ev, err := eventModel(e)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ev)
+	}
+	return res, nil
+}
@@ -0,0 +1,61 @@
+package drop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestHistory(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Old", URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(d.ID)
+
+	title := "New"
+	_, err = drop.Update(ctx, q, user, nil, id, drop.UpdateFields{Title: &title})
+	require.NoError(t, err)
+
+	later := clock.Now().Add(time.Hour)
+	_, err = drop.Move(ctx, q, user, id, drop.StatusRead, later)
+	require.NoError(t, err)
+
+	_, err = drop.Delete(ctx, q, user, id, later)
+	require.NoError(t, err)
+
+	es, err := drop.History(ctx, q, user, id)
+	require.NoError(t, err)
+	require.Len(t, es, 4)
+
+	assert.Equal(t, drop.EventCreate, es[0].Kind)
+	assert.Nil(t, es[0].Before)
+	assert.Equal(t, "Old", es[0].After.Title)
+
+	assert.Equal(t, drop.EventUpdate, es[1].Kind)
+	assert.Equal(t, "Old", es[1].Before.Title)
+	assert.Equal(t, "New", es[1].After.Title)
+
+	assert.Equal(t, drop.EventMove, es[2].Kind)
+	assert.Equal(t, drop.StatusUnread, es[2].Before.Status)
+	assert.WithinDuration(t, clock.Now(), es[2].Before.MovedAt, 0)
+	assert.Equal(t, drop.StatusRead, es[2].After.Status)
+	assert.WithinDuration(t, later, es[2].After.MovedAt, 0)
+
+	assert.Equal(t, drop.EventDelete, es[3].Kind)
+	assert.Equal(t, "New", es[3].Before.Title)
+	assert.Nil(t, es[3].After)
+}
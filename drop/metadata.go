@@ -0,0 +1,73 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/metadata"
+)
+
+// FetchMetadata fills in the page metadata for the oldest untitled drop that
+// hasn't been fetched yet. It returns false if there was nothing to do.
+//
+// The drop stays locked until the transaction ends, so several servers can
+// work through the queue at once without fetching the same page twice.
+func FetchMetadata(ctx context.Context, q db.Queryable, log *zap.Logger, f *metadata.Fetcher, now time.Time) (bool, error) {
+	d, err := q.DropMetadataPending(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	m, err := f.Fetch(ctx, d.URL)
+	if err != nil {
+		// Shutting down isn't the page's fault, so leave it for next time.
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		// Otherwise, mark the drop as fetched anyway so one broken link
+		// can't hold up the queue.
+		log.Info("Could not fetch drop metadata",
+			zap.Stringer("drop_id", d.ID),
+			zap.Error(err))
+	}
+
+	user := api.User{ID: d.UserID}
+	before, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return false, err
+	}
+
+	d, err = q.DropSetMetadata(ctx, db.DropSetMetadataParams{
+		ID:          d.ID,
+		Title:       m.Title,
+		Description: nullString(m.Description),
+		SiteName:    nullString(m.SiteName),
+		ImageURL:    nullString(m.ImageURL),
+		FetchedAt:   now,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	after, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return false, err
+	}
+	return true, recordEvent(ctx, q, user.ID, EventUpdate, &before, &after)
+}
+
+func nullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return db.NullString(&s)
+}
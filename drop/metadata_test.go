@@ -0,0 +1,66 @@
+package drop_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/metadata"
+)
+
+func TestFetchMetadata(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, time.Second)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<head>
+			<title>Example Page</title>
+			<meta property="og:description" content="An example">
+			<meta property="og:site_name" content="Example">
+			<meta property="og:image" content="https://example.com/image.png">
+		</head>`)
+	}))
+	t.Cleanup(srv.Close)
+
+	untitled, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: srv.URL + "/untitled"}, clock.Now())
+	require.NoError(t, err)
+	titled, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "My Title", URL: srv.URL + "/titled"}, clock.Now())
+	require.NoError(t, err)
+
+	f := metadata.NewFetcher(true)
+	for {
+		more, err := drop.FetchMetadata(ctx, q, zap.NewNop(), f, clock.Now())
+		require.NoError(t, err)
+		if !more {
+			break
+		}
+	}
+
+	d, err := drop.Get(ctx, q, user, uuid.FromStringOrNil(untitled.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Example Page", d.Title)
+	assert.Equal(t, "An example", d.Description)
+	assert.Equal(t, "Example", d.SiteName)
+	assert.Equal(t, "https://example.com/image.png", d.ImageURL)
+
+	// Drops that already have titles aren't fetched at all.
+	d, err = drop.Get(ctx, q, user, uuid.FromStringOrNil(titled.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "My Title", d.Title)
+	assert.Empty(t, d.Description)
+}
@@ -0,0 +1,214 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// A Strategy decides which of the matching drops Next picks.
+type Strategy string
+
+const (
+	// StrategyOldest picks the drop that was moved longest ago. This is the
+	// default, since the whole point is to get through the backlog.
+	StrategyOldest Strategy = "oldest"
+	// StrategyNewest picks the most recently moved drop.
+	StrategyNewest Strategy = "newest"
+	// StrategyRandom picks any drop with equal chances.
+	StrategyRandom Strategy = "random"
+	// StrategyWeighted picks at random, but the chance of picking a drop
+	// grows with how long ago it was moved.
+	StrategyWeighted Strategy = "weighted"
+)
+
+// StrategyValueStrings returns all valid values of the enum as strings.
+func StrategyValueStrings() []string {
+	return []string{
+		string(StrategyOldest),
+		string(StrategyNewest),
+		string(StrategyRandom),
+		string(StrategyWeighted),
+	}
+}
+
+func (s Strategy) valid() bool {
+	for _, v := range StrategyValueStrings() {
+		if string(s) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement pflag.Value
+
+func (s *Strategy) String() string {
+	return string(*s)
+}
+
+func (s *Strategy) Set(str string) error {
+	if !Strategy(str).valid() {
+		return fmt.Errorf("%w: %s (expected one of: %s)", ErrUnknownStrategy, str, strings.Join(StrategyValueStrings(), ", "))
+	}
+	*s = Strategy(str)
+	return nil
+}
+
+func (*Strategy) Type() string {
+	return "strategy"
+}
+
+// NextMatching picks the next drop that matches the filters in the body. If
+// there's nothing to filter, it uses the simpler query from Next.
+func NextMatching(ctx context.Context, q db.Queryable, user api.User, body NextBody, now time.Time) (Drop, error) {
+	if !body.filtered() {
+		return Next(ctx, q, user, now)
+	}
+	return FilterNext(ctx, q, user, body, now)
+}
+
+// FilterNext picks the next drop that matches the filters in the body, using
+// its strategy. Like Next, snoozed drops that have woken up count as unread.
+func FilterNext(ctx context.Context, q db.Queryable, user api.User, body NextBody, now time.Time) (Drop, error) {
+	query, err := ParseQuery(body.Query)
+	if err != nil {
+		return Drop{}, err
+	}
+
+	qq := db.Pq.
+		Select(db.DropColumns...).
+		From("drops").
+		Where(sq.Eq{
+			"drops.user_id":    user.ID,
+			"drops.deleted_at": nil,
+		}).
+		Limit(1)
+
+	statuses := body.Statuses
+	if len(statuses) == 0 && len(body.ExcludeStatuses) == 0 && !query.filtersStatus() {
+		statuses = []Status{StatusUnread}
+	}
+	if len(statuses) > 0 {
+		var cond sq.Or
+		for _, s := range statuses {
+			cond = append(cond, statusCond(s, now))
+		}
+		qq = qq.Where(cond)
+	}
+	for _, s := range body.ExcludeStatuses {
+		qq = qq.Where(sq.Expr("NOT (?)", statusCond(s, now)))
+	}
+
+	if len(body.Tags) > 0 {
+		qq = qq.Where(sq.Expr("drops.id IN (?)", taggedAny(user, body.Tags)))
+	}
+	if len(body.ExcludeTags) > 0 {
+		qq = qq.Where(sq.Expr("drops.id NOT IN (?)", taggedAny(user, body.ExcludeTags)))
+	}
+
+	if m := body.MaxReadingMinutes; m != nil {
+		qq = qq.Where(sq.LtOrEq{"drops.reading_minutes": *m})
+	}
+
+	if len(body.Domains) > 0 {
+		qq = qq.Where(domainCond(body.Domains))
+	}
+	if len(body.ExcludeDomains) > 0 {
+		qq = qq.Where(sq.Expr("NOT (?)", domainCond(body.ExcludeDomains)))
+	}
+	if !query.IsZero() {
+		qq = qq.Where(query.cond(user, now))
+	}
+
+	// Pinned drops always come first, whatever the strategy.
+	qq = qq.OrderBy("drops.pinned_at asc nulls last")
+	switch body.Strategy {
+	case StrategyNewest:
+		qq = qq.OrderBy("drops.moved_at desc", "drops.id desc")
+	case StrategyRandom:
+		qq = qq.OrderBy("random()")
+	case StrategyWeighted:
+		// This is weighted random sampling (Efraimidis and Spirakis): give
+		// each drop a random key that tends to be smaller the older it is,
+		// then take the smallest. Drops moved in the last second all get
+		// the same weight.
+		qq = qq.OrderByClause("-ln(1 - random()) / greatest(extract(epoch from ?::timestamp - drops.moved_at), 1)", now)
+	default:
+		qq = qq.OrderBy("drops.moved_at asc", "drops.id asc")
+	}
+
+	stmt, args, err := qq.ToSql()
+	if err != nil {
+		return Drop{}, err
+	}
+
+	rows, err := q.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return Drop{}, err
+	}
+
+	ds, err := db.ScanDrops(rows)
+	if err != nil {
+		return Drop{}, err
+	}
+	if len(ds) == 0 {
+		return Drop{}, sql.ErrNoRows
+	}
+	return loadOne(ctx, q, user, ds[0])
+}
+
+// taggedAny selects the IDs of drops with any of the tags or their
+// descendants.
+func taggedAny(user api.User, tagIDs []uuid.UUID) sq.Sqlizer {
+	return taggedTree(sq.Eq{
+		"tags.user_id": user.ID,
+		"tags.id":      tagIDs,
+	})
+}
+
+// taggedTree selects the IDs of drops with any of the tags matching the
+// condition or their descendants.
+func taggedTree(cond sq.Sqlizer) sq.Sqlizer {
+	return sq.Expr(`WITH RECURSIVE tree AS (
+	SELECT tags.id FROM tags WHERE ?
+	UNION
+	SELECT tags.id FROM tags JOIN tree ON tags.parent_id = tree.id
+) SELECT drop_tags.drop_id FROM drop_tags JOIN tree ON tree.id = drop_tags.tag_id`, cond)
+}
+
+// hostExpr extracts the host name from a drop's URL: the authority, without
+// any user info or port. Squirrel would treat a question mark as a
+// placeholder, so the pattern spells it as \x3f.
+const hostExpr = `lower(regexp_replace(substring(drops.url from '://([^/\x3f#]*)'), '^.*@|:[0-9]*$', '', 'g'))`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// domainCond matches drops with URLs on any of the domains, including their
+// subdomains.
+func domainCond(domains []string) sq.Or {
+	var cond sq.Or
+	for _, d := range domains {
+		d = normalizeDomain(d)
+		cond = append(cond,
+			sq.Expr(hostExpr+" = ?", d),
+			sq.Expr(hostExpr+" LIKE ?", "%."+likeEscaper.Replace(d)),
+		)
+	}
+	return cond
+}
+
+func normalizeDomain(d string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(d), "."))
+}
@@ -0,0 +1,75 @@
+package drop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func TestFilterNext(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	work, err := tag.Create(ctx, q, user, "work")
+	require.NoError(t, err)
+	video, err := tag.Create(ctx, q, user, "video")
+	require.NoError(t, err)
+	workID := uuid.FromStringOrNil(work.ID)
+	videoID := uuid.FromStringOrNil(video.ID)
+
+	create := func(url string, tags []uuid.UUID, age time.Duration) drop.Drop {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: url, TagIDs: tags}, clock.Now().Add(-age))
+		require.NoError(t, err)
+		return d
+	}
+	oldVideo := create("https://www.youtube.com/watch?v=1", []uuid.UUID{workID, videoID}, 3*time.Hour)
+	oldWork := create("https://blog.example.com/post", []uuid.UUID{workID}, 2*time.Hour)
+	newest := create("https://example.org/", nil, time.Hour)
+
+	tests := []struct {
+		name string
+		body drop.NextBody
+		want drop.Drop
+	}{
+		{"tag", drop.NextBody{Tags: []uuid.UUID{workID}}, oldVideo},
+		{"exclude tag", drop.NextBody{Tags: []uuid.UUID{workID}, ExcludeTags: []uuid.UUID{videoID}}, oldWork},
+		{"domain", drop.NextBody{Domains: []string{"example.com"}}, oldWork},
+		{"exclude domain", drop.NextBody{ExcludeDomains: []string{"YouTube.com", "example.com"}}, newest},
+		{"newest", drop.NextBody{Strategy: drop.StrategyNewest}, newest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := drop.FilterNext(ctx, q, user, tt.body, clock.Now())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.ID, d.ID)
+		})
+	}
+
+	for _, s := range []drop.Strategy{drop.StrategyRandom, drop.StrategyWeighted} {
+		d, err := drop.FilterNext(ctx, q, user, drop.NextBody{Tags: []uuid.UUID{workID}, Strategy: s}, clock.Now())
+		require.NoError(t, err)
+		assert.Contains(t, []string{oldVideo.ID, oldWork.ID}, d.ID)
+	}
+
+	_, err = drop.Move(ctx, q, user, uuid.FromStringOrNil(newest.ID), drop.StatusRead, clock.Now())
+	require.NoError(t, err)
+	d, err := drop.FilterNext(ctx, q, user, drop.NextBody{Statuses: []drop.Status{drop.StatusRead}}, clock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, newest.ID, d.ID)
+	d, err = drop.FilterNext(ctx, q, user, drop.NextBody{ExcludeStatuses: []drop.Status{drop.StatusUnread}}, clock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, newest.ID, d.ID)
+}
@@ -0,0 +1,75 @@
+package drop
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// Pin moves an unread drop to the front of the queue. Snoozed drops that are
+// due to wake up count as unread. Pinned drops come
+// before all the others in Next and List, in the order they were pinned, so
+// pinning a drop that's already pinned doesn't change anything.
+//
+// Pinning and unpinning are recorded in the drop's history as moves. Moving or
+// snoozing a drop unpins it.
+func Pin(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, now time.Time) (Drop, error) {
+	before, err := Get(ctx, q, user, id)
+	if err != nil {
+		return Drop{}, err
+	}
+	// A snoozed drop that's due is unread in all but name, so pinning it
+	// wakes it up.
+	due := before.Status == StatusSnoozed && before.SnoozeUntil != nil && !before.SnoozeUntil.After(now)
+	if before.Status != StatusUnread && !due {
+		return Drop{}, api.ValidationError("id", id.String(), "only unread drops can be pinned")
+	}
+
+	if before.PinnedAt != nil {
+		return before, nil
+	}
+
+	d, err := q.DropPin(ctx, db.DropPinParams{
+		UserID:   user.ID,
+		ID:       id,
+		PinnedAt: now,
+	})
+	if err != nil {
+		return Drop{}, err
+	}
+
+	after, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return Drop{}, err
+	}
+	return after, recordEvent(ctx, q, user.ID, EventMove, &before, &after)
+}
+
+// Unpin puts a pinned drop back in its usual place in the queue.
+func Unpin(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Drop, error) {
+	before, err := Get(ctx, q, user, id)
+	if err != nil {
+		return Drop{}, err
+	}
+	if before.PinnedAt == nil {
+		return before, nil
+	}
+
+	d, err := q.DropUnpin(ctx, db.DropUnpinParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if err != nil {
+		return Drop{}, err
+	}
+
+	after, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return Drop{}, err
+	}
+	return after, recordEvent(ctx, q, user.ID, EventMove, &before, &after)
+}
@@ -0,0 +1,135 @@
+package drop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestPin(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	create := func(url string, age time.Duration) uuid.UUID {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: url}, now.Add(-age))
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(d.ID)
+	}
+	oldest := create("https://example.com/1", 3*time.Hour)
+	middle := create("https://example.com/2", 2*time.Hour)
+	newest := create("https://example.com/3", time.Hour)
+
+	// Pins go in the order they were made.
+	d, err := drop.Pin(ctx, q, user, newest, now)
+	require.NoError(t, err)
+	require.NotNil(t, d.PinnedAt)
+	_, err = drop.Pin(ctx, q, user, middle, now.Add(time.Minute))
+	require.NoError(t, err)
+	// Pinning again keeps the original place.
+	_, err = drop.Pin(ctx, q, user, newest, now.Add(2*time.Minute))
+	require.NoError(t, err)
+
+	d, err = drop.Next(ctx, q, user, now)
+	require.NoError(t, err)
+	assert.Equal(t, newest.String(), d.ID)
+
+	d, err = drop.FilterNext(ctx, q, user, drop.NextBody{Strategy: drop.StrategyNewest}, now)
+	require.NoError(t, err)
+	assert.Equal(t, newest.String(), d.ID)
+
+	// Page through one at a time to check the cursors.
+	var ids []string
+	var cursor *drop.Cursor
+	for {
+		ds, err := drop.List(ctx, q, user, drop.StatusUnread, cursor, 1, now)
+		require.NoError(t, err)
+		if len(ds) == 0 {
+			break
+		}
+		ids = append(ids, ds[0].ID)
+		cursor = &drop.Cursor{PinnedAt: ds[0].PinnedAt, MovedAt: ds[0].MovedAt, ID: uuid.FromStringOrNil(ds[0].ID)}
+	}
+	assert.Equal(t, []string{newest.String(), middle.String(), oldest.String()}, ids)
+
+	limit := int32(10)
+	ds, err := drop.Filter(ctx, q, user, drop.ListBody{Limit: &limit, Query: "status:unread"}, now)
+	require.NoError(t, err)
+	require.Len(t, ds, 3)
+	assert.Equal(t, newest.String(), ds[0].ID)
+
+	d, err = drop.Unpin(ctx, q, user, newest)
+	require.NoError(t, err)
+	assert.Nil(t, d.PinnedAt)
+
+	// Pinning the second time didn't change anything, so it isn't in the
+	// history.
+	es, err := drop.History(ctx, q, user, newest)
+	require.NoError(t, err)
+	require.Len(t, es, 3)
+	assert.Equal(t, drop.EventMove, es[1].Kind)
+	assert.Nil(t, es[1].Before.PinnedAt)
+	assert.NotNil(t, es[1].After.PinnedAt)
+	assert.Equal(t, drop.EventMove, es[2].Kind)
+	assert.NotNil(t, es[2].Before.PinnedAt)
+	assert.Nil(t, es[2].After.PinnedAt)
+
+	// Moving a drop unpins it, and only unread drops can be pinned.
+	d, err = drop.Move(ctx, q, user, middle, drop.StatusRead, now)
+	require.NoError(t, err)
+	assert.Nil(t, d.PinnedAt)
+
+	_, err = drop.Pin(ctx, q, user, middle, now)
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+
+	d, err = drop.Next(ctx, q, user, now)
+	require.NoError(t, err)
+	assert.Equal(t, oldest.String(), d.ID)
+}
+
+func TestPinSnoozed(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: "https://example.com"}, now)
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(d.ID)
+	_, err = drop.Snooze(ctx, q, user, id, now.Add(time.Hour), now)
+	require.NoError(t, err)
+
+	// Still asleep.
+	_, err = drop.Pin(ctx, q, user, id, now)
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+
+	// Due, so pinning wakes it up.
+	later := now.Add(2 * time.Hour)
+	d, err = drop.Pin(ctx, q, user, id, later)
+	require.NoError(t, err)
+	assert.Equal(t, drop.StatusUnread, d.Status)
+	assert.Nil(t, d.SnoozeUntil)
+	require.NotNil(t, d.PinnedAt)
+	assert.WithinDuration(t, later, *d.PinnedAt, 0)
+}
@@ -0,0 +1,527 @@
+package drop
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/metagram-net/firehose/api"
+)
+
+// A Query is a parsed filter in the drop query language. A query is a list of
+// terms, which all have to match:
+//
+//	status:unread tag:work -tag:video domain:arxiv.org before:2026-01-01
+//
+// The terms are:
+//
+//	status:S      drops with the status (snoozed drops that have woken up count as unread)
+//	tag:NAME      drops with the tag
+//	untagged      drops without any tags
+//	domain:D      drops with URLs on the domain or its subdomains
+//	before:DATE   drops created before the date (YYYY-MM-DD)
+//	after:DATE    drops created on or after the date
+//	WORD          drops with the word in their title or URL
+//
+// Values with spaces can be quoted, like tag:"to do". Terms can be negated
+// with a leading - or NOT, combined with OR, and grouped with parentheses.
+// AND is optional, and binds tighter than OR:
+//
+//	(tag:work OR tag:study) NOT domain:youtube.com
+type Query struct {
+	// root is nil for the empty query, which matches everything.
+	root queryNode
+}
+
+// QueryErrorDetails are the details of the validation error for a query that
+// couldn't be parsed.
+type QueryErrorDetails struct {
+	// Position is where the problem is in the query, counting characters
+	// from 1.
+	Position int `json:"position"`
+}
+
+// ParseQuery parses a query. The error is a validation error for the query
+// field, with the position of the problem in the details.
+func ParseQuery(s string) (Query, error) {
+	p := queryParser{src: s}
+	if err := p.lex(); err != nil {
+		return Query{}, err
+	}
+	if len(p.toks) == 0 {
+		return Query{}, nil
+	}
+
+	root, err := p.or()
+	if err != nil {
+		return Query{}, err
+	}
+	if t := p.peek(); t != nil {
+		if t.kind == tokRParen {
+			return Query{}, p.errorAt(t.pos, "unexpected )")
+		}
+		return Query{}, p.errorAt(t.pos, fmt.Sprintf("unexpected %s", t.text))
+	}
+	return Query{root: root}, nil
+}
+
+// String formats the query with explicit ANDs and parentheses, which shows
+// how it was parsed.
+func (q Query) String() string {
+	if q.root == nil {
+		return ""
+	}
+	return q.root.String()
+}
+
+// IsZero reports whether the query is empty.
+func (q Query) IsZero() bool {
+	return q.root == nil
+}
+
+// filtersStatus reports whether any of the terms is a status.
+func (q Query) filtersStatus() bool {
+	var walk func(n queryNode) bool
+	walk = func(n queryNode) bool {
+		switch n := n.(type) {
+		case andNode:
+			for _, c := range n {
+				if walk(c) {
+					return true
+				}
+			}
+		case orNode:
+			for _, c := range n {
+				if walk(c) {
+					return true
+				}
+			}
+		case notNode:
+			return walk(n.queryNode)
+		case statusTerm:
+			return true
+		}
+		return false
+	}
+	return q.root != nil && walk(q.root)
+}
+
+// cond converts the query to a condition on the drops table.
+func (q Query) cond(user api.User, now time.Time) sq.Sqlizer {
+	if q.root == nil {
+		return sq.Expr("TRUE")
+	}
+	return q.root.cond(user, now)
+}
+
+type queryNode interface {
+	fmt.Stringer
+	cond(user api.User, now time.Time) sq.Sqlizer
+}
+
+type andNode []queryNode
+
+func (n andNode) String() string {
+	parts := make([]string, 0, len(n))
+	for _, c := range n {
+		parts = append(parts, c.String())
+	}
+	return "(" + strings.Join(parts, " AND ") + ")"
+}
+
+func (n andNode) cond(user api.User, now time.Time) sq.Sqlizer {
+	var c sq.And
+	for _, m := range n {
+		c = append(c, m.cond(user, now))
+	}
+	return c
+}
+
+type orNode []queryNode
+
+func (n orNode) String() string {
+	parts := make([]string, 0, len(n))
+	for _, c := range n {
+		parts = append(parts, c.String())
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+func (n orNode) cond(user api.User, now time.Time) sq.Sqlizer {
+	var c sq.Or
+	for _, m := range n {
+		c = append(c, m.cond(user, now))
+	}
+	return c
+}
+
+type notNode struct {
+	queryNode
+}
+
+func (n notNode) String() string {
+	return "NOT " + n.queryNode.String()
+}
+
+func (n notNode) cond(user api.User, now time.Time) sq.Sqlizer {
+	return sq.Expr("NOT (?)", n.queryNode.cond(user, now))
+}
+
+type statusTerm Status
+
+func (t statusTerm) String() string {
+	return "status:" + Status(t).String()
+}
+
+func (t statusTerm) cond(_ api.User, now time.Time) sq.Sqlizer {
+	return statusCond(Status(t), now)
+}
+
+type tagTerm string
+
+func (t tagTerm) String() string {
+	return "tag:" + quoteTerm(string(t))
+}
+
+func (t tagTerm) cond(user api.User, _ time.Time) sq.Sqlizer {
+	// Other users can have tags with the same name, so only start from the
+	// user's own. Their descendants belong to the user too.
+	return sq.Expr("drops.id IN (?)", taggedTree(sq.Eq{
+		"tags.user_id": user.ID,
+		"tags.name":    string(t),
+	}))
+}
+
+type untaggedTerm struct{}
+
+func (untaggedTerm) String() string {
+	return "untagged"
+}
+
+func (untaggedTerm) cond(api.User, time.Time) sq.Sqlizer {
+	return sq.Expr("NOT EXISTS (SELECT 1 FROM drop_tags WHERE drop_tags.drop_id = drops.id)")
+}
+
+type domainTerm string
+
+func (t domainTerm) String() string {
+	return "domain:" + quoteTerm(string(t))
+}
+
+func (t domainTerm) cond(api.User, time.Time) sq.Sqlizer {
+	return domainCond([]string{string(t)})
+}
+
+type beforeTerm time.Time
+
+func (t beforeTerm) String() string {
+	return "before:" + time.Time(t).Format(queryDateLayout)
+}
+
+func (t beforeTerm) cond(api.User, time.Time) sq.Sqlizer {
+	return sq.Lt{"drops.created_at": time.Time(t)}
+}
+
+type afterTerm time.Time
+
+func (t afterTerm) String() string {
+	return "after:" + time.Time(t).Format(queryDateLayout)
+}
+
+func (t afterTerm) cond(api.User, time.Time) sq.Sqlizer {
+	return sq.GtOrEq{"drops.created_at": time.Time(t)}
+}
+
+type textTerm string
+
+func (t textTerm) String() string {
+	return quoteTerm(string(t))
+}
+
+func (t textTerm) cond(api.User, time.Time) sq.Sqlizer {
+	pattern := "%" + likeEscaper.Replace(string(t)) + "%"
+	return sq.Or{
+		sq.ILike{"drops.title": pattern},
+		sq.ILike{"drops.url": pattern},
+	}
+}
+
+const queryDateLayout = "2006-01-02"
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteTerm quotes a value if it would be parsed differently without quotes.
+func quoteTerm(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"():") || strings.HasPrefix(s, "-") || isKeyword(s) || s == "untagged" {
+		return `"` + quoteEscaper.Replace(s) + `"`
+	}
+	return s
+}
+
+func isKeyword(s string) bool {
+	return s == "AND" || s == "OR" || s == "NOT"
+}
+
+type tokenKind int
+
+const (
+	tokTerm tokenKind = iota
+	tokLParen
+	tokRParen
+	tokNot
+	tokAnd
+	tokOr
+)
+
+type token struct {
+	kind tokenKind
+	// pos is the position of the token, counting characters from 1.
+	pos int
+	// text is the token as written, for error messages.
+	text string
+
+	// Terms are split into the key (if any) and the value, with quotes
+	// removed.
+	key      string
+	value    string
+	valuePos int
+}
+
+type queryParser struct {
+	src  string
+	toks []token
+	next int
+}
+
+func (p *queryParser) errorAt(pos int, msg string) error {
+	err := api.ValidationError("query", p.src, fmt.Sprintf("at position %d: %s", pos, msg))
+	err.Details = &QueryErrorDetails{Position: pos}
+	return err
+}
+
+// lex splits the query into tokens.
+func (p *queryParser) lex() error {
+	rs := []rune(p.src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			p.toks = append(p.toks, token{kind: tokLParen, pos: i + 1, text: "("})
+			i++
+		case r == ')':
+			p.toks = append(p.toks, token{kind: tokRParen, pos: i + 1, text: ")"})
+			i++
+		case r == '-' && i+1 < len(rs) && !unicode.IsSpace(rs[i+1]) && rs[i+1] != ')':
+			p.toks = append(p.toks, token{kind: tokNot, pos: i + 1, text: "-"})
+			i++
+		default:
+			t, end, err := p.lexTerm(rs, i)
+			if err != nil {
+				return err
+			}
+			p.toks = append(p.toks, t)
+			i = end
+		}
+	}
+	return nil
+}
+
+// lexTerm reads the term starting at rs[start]. It returns the index just
+// past the end of the term.
+func (p *queryParser) lexTerm(rs []rune, start int) (token, int, error) {
+	t := token{kind: tokTerm, pos: start + 1, valuePos: start + 1}
+
+	var b strings.Builder
+	quoted := false
+	i := start
+	for i < len(rs) {
+		r := rs[i]
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+		switch {
+		case r == '"':
+			quoted = true
+			open := i
+			i++
+			closed := false
+			for i < len(rs) {
+				if rs[i] == '\\' && i+1 < len(rs) {
+					b.WriteRune(rs[i+1])
+					i += 2
+					continue
+				}
+				if rs[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(rs[i])
+				i++
+			}
+			if !closed {
+				return token{}, 0, p.errorAt(open+1, "unterminated quote")
+			}
+		case r == ':' && t.key == "" && !quoted:
+			// Only the first colon separates the key, and only if the key
+			// wasn't quoted.
+			t.key = b.String()
+			t.valuePos = i + 2
+			b.Reset()
+			i++
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	t.text = string(rs[start:i])
+	t.value = b.String()
+
+	if t.key == "" && !quoted {
+		switch t.value {
+		case "AND":
+			t.kind = tokAnd
+		case "OR":
+			t.kind = tokOr
+		case "NOT":
+			t.kind = tokNot
+		}
+	}
+	return t, i, nil
+}
+
+func (p *queryParser) peek() *token {
+	if p.next < len(p.toks) {
+		return &p.toks[p.next]
+	}
+	return nil
+}
+
+// or parses terms separated by OR.
+func (p *queryParser) or() (queryNode, error) {
+	first, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	nodes := orNode{first}
+	for {
+		t := p.peek()
+		if t == nil || t.kind != tokOr {
+			break
+		}
+		p.next++
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+// and parses terms separated by AND, or just next to each other.
+func (p *queryParser) and() (queryNode, error) {
+	first, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	nodes := andNode{first}
+	for {
+		t := p.peek()
+		if t == nil || t.kind == tokOr || t.kind == tokRParen {
+			break
+		}
+		if t.kind == tokAnd {
+			p.next++
+		}
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+// unary parses a term or group, which might be negated.
+func (p *queryParser) unary() (queryNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, p.errorAt(len([]rune(p.src))+1, "expected a term")
+	}
+	switch t.kind {
+	case tokNot:
+		p.next++
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case tokLParen:
+		p.next++
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.peek(); c == nil || c.kind != tokRParen {
+			return nil, p.errorAt(t.pos, "missing )")
+		}
+		p.next++
+		return n, nil
+	case tokTerm:
+		p.next++
+		return p.term(*t)
+	default:
+		return nil, p.errorAt(t.pos, fmt.Sprintf("expected a term, got %s", t.text))
+	}
+}
+
+func (p *queryParser) term(t token) (queryNode, error) {
+	if t.key == "" {
+		if t.value == "untagged" && t.text == "untagged" {
+			return untaggedTerm{}, nil
+		}
+		if t.value == "" {
+			return nil, p.errorAt(t.pos, "empty term")
+		}
+		return textTerm(t.value), nil
+	}
+
+	if t.value == "" {
+		return nil, p.errorAt(t.valuePos, fmt.Sprintf("missing value for %s:", t.key))
+	}
+	switch t.key {
+	case "status":
+		s, err := StatusString(t.value)
+		if err != nil || s == StatusUnknown {
+			return nil, p.errorAt(t.valuePos, fmt.Sprintf("unknown status %q (expected one of: %s)", t.value, strings.Join(StatusValueStrings(), ", ")))
+		}
+		return statusTerm(s), nil
+	case "tag":
+		return tagTerm(t.value), nil
+	case "domain":
+		return domainTerm(normalizeDomain(t.value)), nil
+	case "before", "after":
+		d, err := time.Parse(queryDateLayout, t.value)
+		if err != nil {
+			return nil, p.errorAt(t.valuePos, fmt.Sprintf("invalid date %q (expected YYYY-MM-DD)", t.value))
+		}
+		if t.key == "before" {
+			return beforeTerm(d), nil
+		}
+		return afterTerm(d), nil
+	default:
+		return nil, p.errorAt(t.pos, fmt.Sprintf("unknown filter %q (expected one of: status, tag, domain, before, after)", t.key))
+	}
+}
@@ -0,0 +1,200 @@
+package drop_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "",
+		"status:unread":                      "status:unread",
+		"status:unread tag:work":             "(status:unread AND tag:work)",
+		"a AND b":                            "(a AND b)",
+		"a b OR c":                           "((a AND b) OR c)",
+		"a (b OR c)":                         "(a AND (b OR c))",
+		"-tag:video":                         "NOT tag:video",
+		"NOT (a OR b)":                       "NOT (a OR b)",
+		`tag:"to do" "or"`:                   `(tag:"to do" AND or)`,
+		"domain:ArXiv.org.":                  "domain:arxiv.org",
+		"untagged -untagged":                 "(untagged AND NOT untagged)",
+		"before:2026-01-01 after:2025-06-30": "(before:2026-01-01 AND after:2025-06-30)",
+		`"untagged" "-x"`:                    `("untagged" AND "-x")`,
+		"a - b":                              `(a AND "-" AND b)`,
+	}
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			q, err := drop.ParseQuery(in)
+			require.NoError(t, err)
+			assert.Equal(t, want, q.String())
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := map[string]int{
+		"status:nope":         8,
+		"tag:work color:red":  10,
+		"before:yesterday":    8,
+		"tag:":                5,
+		`tag:"unterminated`:   5,
+		"(tag:work":           1,
+		"tag:work)":           9,
+		"tag:work OR":         12,
+		"tag:work AND OR x":   14,
+		"tag:wörk status:red": 17,
+	}
+	for in, pos := range tests {
+		t.Run(in, func(t *testing.T) {
+			_, err := drop.ParseQuery(in)
+			var aerr api.Error
+			require.ErrorAs(t, err, &aerr)
+			assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+			assert.Equal(t, &drop.QueryErrorDetails{Position: pos}, aerr.Details)
+		})
+	}
+}
+
+func TestFilterQuery(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	work, err := tag.Create(ctx, q, user, "work")
+	require.NoError(t, err)
+	video, err := tag.Create(ctx, q, user, "video")
+	require.NoError(t, err)
+	workID := uuid.FromStringOrNil(work.ID)
+	videoID := uuid.FromStringOrNil(video.ID)
+
+	create := func(title, url string, tags []uuid.UUID, age time.Duration) string {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: title, URL: url, TagIDs: tags}, clock.Now().Add(-age))
+		require.NoError(t, err)
+		return d.ID
+	}
+	talk := create("A talk", "https://www.youtube.com/watch?v=1", []uuid.UUID{workID, videoID}, 48*time.Hour)
+	paper := create("A paper", "https://arxiv.org/abs/1", []uuid.UUID{workID}, 24*time.Hour)
+	loose := create("Something else", "https://example.org/", nil, time.Hour)
+
+	limit := int32(10)
+	list := func(query string) []string {
+		ds, err := drop.Filter(ctx, q, user, drop.ListBody{Limit: &limit, Query: query}, clock.Now())
+		require.NoError(t, err)
+		var ids []string
+		for _, d := range ds {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{talk, paper, loose}, list("status:unread"))
+	assert.Equal(t, []string{paper}, list("tag:work -tag:video"))
+	assert.Equal(t, []string{loose}, list("untagged"))
+	assert.Equal(t, []string{talk, loose}, list("tag:video OR untagged"))
+	assert.Equal(t, []string{paper}, list("domain:arxiv.org"))
+	assert.Equal(t, []string{talk, paper}, list("NOT domain:example.org"))
+	assert.Equal(t, []string{talk}, list("TALK"))
+	assert.Equal(t, []string{talk}, list("before:2021-10-31"))
+	assert.Equal(t, []string{paper, loose}, list("after:2021-10-31"))
+	assert.Empty(t, list("status:read"))
+}
+
+func TestFilterTagDescendants(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	code, err := tag.Create(ctx, q, user, "code")
+	require.NoError(t, err)
+	golang, err := tag.Create(ctx, q, user, "go")
+	require.NoError(t, err)
+	codeID := uuid.FromStringOrNil(code.ID)
+	goID := uuid.FromStringOrNil(golang.ID)
+	_, err = tag.SetParent(ctx, q, user, goID, &codeID)
+	require.NoError(t, err)
+
+	create := func(title string, tags []uuid.UUID, age time.Duration) string {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: title, URL: "https://example.com/" + title, TagIDs: tags}, clock.Now().Add(-age))
+		require.NoError(t, err)
+		return d.ID
+	}
+	general := create("general", []uuid.UUID{codeID}, 2*time.Hour)
+	specific := create("specific", []uuid.UUID{goID}, time.Hour)
+
+	limit := int32(10)
+	list := func(body drop.ListBody) []string {
+		body.Limit = &limit
+		ds, err := drop.Filter(ctx, q, user, body, clock.Now())
+		require.NoError(t, err)
+		var ids []string
+		for _, d := range ds {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{general, specific}, list(drop.ListBody{Tags: &[]uuid.UUID{codeID}}))
+	assert.Equal(t, []string{specific}, list(drop.ListBody{Tags: &[]uuid.UUID{goID}}))
+	assert.Equal(t, []string{general, specific}, list(drop.ListBody{Query: "tag:code"}))
+	assert.Equal(t, []string{specific}, list(drop.ListBody{Query: "tag:go"}))
+
+	_, err = drop.FilterNext(ctx, q, user, drop.NextBody{ExcludeTags: []uuid.UUID{codeID}}, clock.Now())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	d, err := drop.FilterNext(ctx, q, user, drop.NextBody{Tags: []uuid.UUID{codeID}, Strategy: drop.StrategyNewest}, clock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, specific, d.ID)
+}
+
+func TestFilterQueryTagOtherUser(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		other = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	create := func(user api.User, url string) string {
+		work, err := tag.Create(ctx, q, user, "work")
+		require.NoError(t, err)
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: url, TagIDs: []uuid.UUID{uuid.FromStringOrNil(work.ID)}}, clock.Now())
+		require.NoError(t, err)
+		return d.ID
+	}
+	mine := create(user, "https://example.com/mine")
+	theirs := create(other, "https://example.com/theirs")
+
+	limit := int32(10)
+	list := func(user api.User) []string {
+		ds, err := drop.Filter(ctx, q, user, drop.ListBody{Limit: &limit, Query: "tag:work"}, clock.Now())
+		require.NoError(t, err)
+		var ids []string
+		for _, d := range ds {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{mine}, list(user))
+	assert.Equal(t, []string{theirs}, list(other))
+}
@@ -0,0 +1,127 @@
+package drop
+
+import (
+	"context"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// ruleCond matches the drops that a tag rule applies to.
+func ruleCond(r db.TagRule) sq.Sqlizer {
+	switch r.Match {
+	case db.TagRuleMatchDomain:
+		return domainCond([]string{r.Pattern})
+	case db.TagRuleMatchUrl:
+		return sq.Expr("drops.url ~ ?", r.Pattern)
+	default:
+		// Every keyword has to be somewhere in the title.
+		cond := sq.And{}
+		for _, k := range strings.Fields(r.Pattern) {
+			cond = append(cond, sq.ILike{"drops.title": "%" + likeEscaper.Replace(k) + "%"})
+		}
+		return cond
+	}
+}
+
+// ruleMatches selects the columns from the user's drops that match the rule
+// but don't have its tag yet.
+func ruleMatches(r db.TagRule, columns ...string) sq.SelectBuilder {
+	return db.Pq.
+		Select(columns...).
+		From("drops").
+		Where(sq.Eq{
+			"drops.user_id":    r.UserID,
+			"drops.deleted_at": nil,
+		}).
+		Where(ruleCond(r)).
+		Where("NOT EXISTS (SELECT 1 FROM drop_tags WHERE drop_tags.drop_id = drops.id AND drop_tags.tag_id = ?)", r.TagID)
+}
+
+// RuleMatching lists the drops that the rule would tag if it were applied
+// now, oldest first and at most limit of them, along with how many there are
+// in total. This doesn't change anything, so it's safe for dry runs.
+func RuleMatching(ctx context.Context, q db.Queryable, r db.TagRule, limit int32) ([]Drop, int64, error) {
+	var n int64
+	stmt, args, err := ruleMatches(r, "count(*)").ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := q.QueryRowContext(ctx, stmt, args...).Scan(&n); err != nil {
+		return nil, 0, err
+	}
+
+	stmt, args, err = ruleMatches(r, db.DropColumns...).
+		OrderBy("drops.moved_at asc", "drops.id asc").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := q.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ds, err := db.ScanDrops(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := loadMany(ctx, q, api.User{ID: r.UserID}, ds)
+	return res, n, err
+}
+
+// ApplyRule tags every drop that matches the rule, recording a tag event for
+// each one. This returns the drops it tagged, oldest first.
+func ApplyRule(ctx context.Context, q db.Queryable, r db.TagRule) ([]Drop, error) {
+	stmt, args, err := ruleMatches(r, db.DropColumns...).
+		OrderBy("drops.moved_at asc", "drops.id asc").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := db.ScanDrops(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	user := api.User{ID: r.UserID}
+	res := make([]Drop, 0, len(ds))
+	for _, d := range ds {
+		tagged, err := retag(ctx, q, user, d.ID, []uuid.UUID{r.TagID}, nil)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, tagged)
+	}
+	return res, nil
+}
+
+// applyRules tags a new drop with the tags of the rules that match it. The
+// rules must belong to the drop's user. This doesn't record any events, since
+// the drop's create event should have the tags.
+func applyRules(ctx context.Context, q db.Queryable, rs []db.TagRule, d db.Drop) error {
+	for _, r := range rs {
+		stmt, args, err := db.Pq.
+			Insert("drop_tags").
+			Columns("drop_id", "tag_id").
+			Select(ruleMatches(r, "drops.id").
+				Column("?::uuid", r.TagID).
+				Where(sq.Eq{"drops.id": d.ID})).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, stmt, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package drop
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// Snooze hides a drop until the given time. When it wakes up, it's unread and
+// takes its place in the queue as if it had been dropped then.
+func Snooze(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, until time.Time, now time.Time) (Drop, error) {
+	if !until.After(now) {
+		return Drop{}, api.ValidationError("until", until.Format(time.RFC3339), "must be in the future")
+	}
+
+	before, err := Get(ctx, q, user, id)
+	if err != nil {
+		return Drop{}, err
+	}
+
+	// Timestamps are stored without zones, so use the same one as the other
+	// moved_at times for the queue order to make sense.
+	d, err := q.DropSnooze(ctx, db.DropSnoozeParams{
+		UserID:      user.ID,
+		ID:          id,
+		SnoozeUntil: until.In(now.Location()),
+	})
+	if err != nil {
+		return Drop{}, err
+	}
+
+	after, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return Drop{}, err
+	}
+	return after, recordEvent(ctx, q, user.ID, EventMove, &before, &after)
+}
+
+// Wake moves all snoozed drops that are due back to unread. Next and List
+// already treat them as unread, so this only keeps the stored status in sync.
+func Wake(ctx context.Context, q db.Queryable, now time.Time) error {
+	ds, err := q.DropsWake(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range ds {
+		user := api.User{ID: d.UserID}
+		after, err := loadOne(ctx, q, user, d)
+		if err != nil {
+			return err
+		}
+		before := after
+		before.Status = StatusSnoozed
+		before.SnoozeUntil = &d.MovedAt
+		if err := recordEvent(ctx, q, user.ID, EventMove, &before, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package drop_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestSnooze(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Later", URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(d.ID)
+
+	_, err = drop.Snooze(ctx, q, user, id, clock.Now().Add(-time.Hour), clock.Now())
+	assert.Error(t, err)
+
+	until := clock.Now().Add(time.Hour)
+	d, err = drop.Snooze(ctx, q, user, id, until, clock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, drop.StatusSnoozed, d.Status)
+
+	_, err = drop.Next(ctx, q, user, clock.Now())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	next, err := drop.Next(ctx, q, user, until)
+	require.NoError(t, err)
+	assert.Equal(t, d.ID, next.ID)
+
+	require.NoError(t, drop.Wake(ctx, q, until))
+	d, err = drop.Get(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Equal(t, drop.StatusUnread, d.Status)
+	assert.Nil(t, d.SnoozeUntil)
+}
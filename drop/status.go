@@ -15,6 +15,8 @@ const (
 	StatusUnread                // unread
 	StatusRead                  // read
 	StatusSaved                 // saved
+	StatusSnoozed               // snoozed
+	StatusExpired               // expired
 )
 
 // StatusValueStrings returns all valid values of the enum as strings.
@@ -23,6 +25,19 @@ func StatusValueStrings() []string {
 		StatusUnread.String(),
 		StatusRead.String(),
 		StatusSaved.String(),
+		StatusSnoozed.String(),
+		StatusExpired.String(),
+	}
+}
+
+// MoveStatusValueStrings returns the statuses drops can be moved to as
+// strings. Snoozing needs a wake-up time, so it isn't one of them.
+func MoveStatusValueStrings() []string {
+	return []string{
+		StatusUnread.String(),
+		StatusRead.String(),
+		StatusSaved.String(),
+		StatusExpired.String(),
 	}
 }
 
@@ -36,6 +51,10 @@ func StatusModel(s db.DropStatus) Status {
 		return StatusRead
 	case db.DropStatusSaved:
 		return StatusSaved
+	case db.DropStatusSnoozed:
+		return StatusSnoozed
+	case db.DropStatusExpired:
+		return StatusExpired
 	default:
 		panic(fmt.Sprintf("unknown status: %s", s))
 	}
@@ -51,6 +70,10 @@ func (s Status) Model() db.DropStatus {
 		return db.DropStatusRead
 	case StatusSaved:
 		return db.DropStatusSaved
+	case StatusSnoozed:
+		return db.DropStatusSnoozed
+	case StatusExpired:
+		return db.DropStatusExpired
 	default:
 		panic(fmt.Sprintf("unrecognized status: %s", s))
 	}
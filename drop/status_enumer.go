@@ -8,9 +8,9 @@ import (
 	"fmt"
 )
 
-const _StatusName = "unknownunreadreadsaved"
+const _StatusName = "unknownunreadreadsavedsnoozedexpired"
 
-var _StatusIndex = [...]uint8{0, 7, 13, 17, 22}
+var _StatusIndex = [...]uint8{0, 7, 13, 17, 22, 29, 36}
 
 func (i Status) String() string {
 	if i < 0 || i >= Status(len(_StatusIndex)-1) {
@@ -19,13 +19,15 @@ func (i Status) String() string {
 	return _StatusName[_StatusIndex[i]:_StatusIndex[i+1]]
 }
 
-var _StatusValues = []Status{0, 1, 2, 3}
+var _StatusValues = []Status{0, 1, 2, 3, 4, 5}
 
 var _StatusNameToValueMap = map[string]Status{
 	_StatusName[0:7]:   0,
 	_StatusName[7:13]:  1,
 	_StatusName[13:17]: 2,
 	_StatusName[17:22]: 3,
+	_StatusName[22:29]: 4,
+	_StatusName[29:36]: 5,
 }
 
 // StatusString retrieves an enum value from the enum constants string name.
@@ -0,0 +1,100 @@
+package drop
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// findOrCreateTags returns the user's tags with the given names, keyed by
+// name, creating any that don't exist yet. Blank names are ignored.
+func findOrCreateTags(ctx context.Context, q db.Queryable, user api.User, names []string) (map[string]db.Tag, error) {
+	var uniq []string
+	seen := make(map[string]bool)
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		uniq = append(uniq, n)
+	}
+
+	tags := make(map[string]db.Tag)
+	if len(uniq) == 0 {
+		return tags, nil
+	}
+
+	// Tag names are unique, so this skips the ones that already exist (or
+	// that a concurrent request creates first) and then finds all of them.
+	_, err := q.TagsCreateMissing(ctx, db.TagsCreateMissingParams{
+		UserID: user.ID,
+		Names:  uniq,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ts, err := q.TagFindByNames(ctx, db.TagFindByNamesParams{
+		UserID: user.ID,
+		Names:  uniq,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range ts {
+		tags[t.Name] = t
+	}
+	return tags, nil
+}
+
+// withNamedTags adds the IDs of the tags with the given names to ids, creating
+// any tags that don't exist yet.
+func withNamedTags(ctx context.Context, q db.Queryable, user api.User, ids []uuid.UUID, names []string) ([]uuid.UUID, error) {
+	tags, err := findOrCreateTags(ctx, q, user, names)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]uuid.UUID, 0, len(ids)+len(tags))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	for _, n := range names {
+		t, ok := tags[strings.TrimSpace(n)]
+		if ok && !seen[t.ID] {
+			seen[t.ID] = true
+			res = append(res, t.ID)
+		}
+	}
+	return res, nil
+}
+
+// checkTags returns a no-resource error for the first of the tags that
+// doesn't belong to the user.
+func checkTags(ctx context.Context, q db.Queryable, user api.User, ids []uuid.UUID) error {
+	ts, err := q.TagFindAll(ctx, db.TagFindAllParams{
+		UserID: user.ID,
+		Ids:    ids,
+	})
+	if err != nil {
+		return err
+	}
+	found := make(map[uuid.UUID]bool, len(ts))
+	for _, t := range ts {
+		found[t.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return api.NoResourceError("tag", id.String())
+		}
+	}
+	return nil
+}
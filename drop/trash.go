@@ -0,0 +1,111 @@
+package drop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// PurgeBatchSize is the most drops Purge deletes at a time.
+const PurgeBatchSize = 100
+
+// Delete moves a drop to the trash. Trashed drops are hidden everywhere else
+// until they're restored, and are purged for good after a while.
+func Delete(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, now time.Time) (Drop, error) {
+	before, err := Get(ctx, q, user, id)
+	if err != nil {
+		return Drop{}, err
+	}
+
+	d, err := q.DropTrash(ctx, db.DropTrashParams{
+		UserID:    user.ID,
+		ID:        id,
+		DeletedAt: now,
+	})
+	if err != nil {
+		return Drop{}, err
+	}
+
+	res, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return Drop{}, err
+	}
+	return res, recordEvent(ctx, q, user.ID, EventDelete, &before, nil)
+}
+
+// Restore takes a drop back out of the trash, as it was when it was deleted.
+// If the user has dropped the same URL again since then, this returns a
+// duplicate error instead.
+func Restore(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Drop, error) {
+	d, err := q.DropFindTrashed(ctx, db.DropFindTrashedParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Drop{}, api.NoResourceError("trashed drop", id.String())
+	}
+	if err != nil {
+		return Drop{}, err
+	}
+
+	dup, err := findDuplicate(ctx, q, user, d.CanonicalURL)
+	if err != nil {
+		return Drop{}, err
+	}
+	if dup != nil {
+		return Drop{}, duplicateError(ctx, q, user, *dup)
+	}
+
+	d, err = q.DropRestore(ctx, db.DropRestoreParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if err != nil {
+		return Drop{}, err
+	}
+
+	after, err := loadOne(ctx, q, user, d)
+	if err != nil {
+		return Drop{}, err
+	}
+	return after, recordEvent(ctx, q, user.ID, EventRestore, nil, &after)
+}
+
+// Trash lists the drops in the trash, most recently deleted first.
+func Trash(ctx context.Context, q db.Queryable, user api.User) ([]Drop, error) {
+	ds, err := q.DropsTrashed(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loadMany(ctx, q, user, ds)
+}
+
+// EmptyTrash permanently deletes all the drops in the trash. It returns the
+// number of drops deleted.
+func EmptyTrash(ctx context.Context, q db.Queryable, user api.User) (int, error) {
+	ids, err := q.DropsEmptyTrash(ctx, user.ID)
+	return len(ids), err
+}
+
+// Purge permanently deletes drops that were trashed before the given time,
+// for all users. It deletes at most PurgeBatchSize drops at a time, and
+// returns true if there might be more left.
+//
+// Like the other background jobs, the drops are locked with skip locked, so
+// several servers can purge at once.
+func Purge(ctx context.Context, q db.Queryable, before time.Time) (bool, error) {
+	ids, err := q.DropsPurge(ctx, db.DropsPurgeParams{
+		TrashedBefore: before,
+		MaxDrops:      PurgeBatchSize,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(ids) == PurgeBatchSize, nil
+}
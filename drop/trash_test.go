@@ -0,0 +1,79 @@
+package drop_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+)
+
+func TestTrash(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Oops", URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(d.ID)
+
+	d, err = drop.Delete(ctx, q, user, id, clock.Now())
+	require.NoError(t, err)
+	assert.NotNil(t, d.DeletedAt)
+
+	_, err = drop.Get(ctx, q, user, id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = drop.Next(ctx, q, user, clock.Now())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	trash, err := drop.Trash(ctx, q, user)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, d.ID, trash[0].ID)
+
+	// The URL can be dropped again while the old one is in the trash, but
+	// then the old one can't come back.
+	again, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Again", URL: "https://example.net"}, clock.Now())
+	require.NoError(t, err)
+	_, err = drop.Restore(ctx, q, user, id)
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("duplicate_resource"), aerr.Code)
+
+	_, err = drop.Delete(ctx, q, user, uuid.FromStringOrNil(again.ID), clock.Now())
+	require.NoError(t, err)
+	d, err = drop.Restore(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Nil(t, d.DeletedAt)
+	assert.Equal(t, drop.StatusUnread, d.Status)
+
+	n, err := drop.EmptyTrash(ctx, q, user)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = drop.Delete(ctx, q, user, id, clock.Now())
+	require.NoError(t, err)
+	more, err := drop.Purge(ctx, q, clock.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.False(t, more)
+	trash, err = drop.Trash(ctx, q, user)
+	require.NoError(t, err)
+	assert.Len(t, trash, 1)
+
+	_, err = drop.Purge(ctx, q, clock.Now())
+	require.NoError(t, err)
+	trash, err = drop.Trash(ctx, q, user)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+}
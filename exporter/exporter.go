@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown export format")
+	ErrUnsupportedVersion = errors.New("unsupported export version")
+	ErrDirectoryFormat    = errors.New("export format writes a directory, not a file")
+)
+
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatHTML  Format = "html"
+	FormatOPML  Format = "opml"
+	// FormatMarkdown writes one file per drop, so it uses NewMarkdownWriter
+	// instead of NewWriter.
+	FormatMarkdown Format = "markdown"
+)
+
+// FormatValueStrings returns all valid values of the enum as strings.
+func FormatValueStrings() []string {
+	return []string{
+		string(FormatJSONL),
+		string(FormatHTML),
+		string(FormatOPML),
+		string(FormatMarkdown),
+	}
+}
+
+// Implement pflag.Value
+
+func (f *Format) String() string {
+	return string(*f)
+}
+
+func (f *Format) Set(s string) error {
+	for _, v := range FormatValueStrings() {
+		if s == v {
+			*f = Format(s)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s (expected one of: %s)", ErrUnknownFormat, s, strings.Join(FormatValueStrings(), ", "))
+}
+
+func (*Format) Type() string {
+	return "exportFormat"
+}
+
+// A Writer encodes an export stream in some file format. WriteHeader must be
+// called first, then WriteDrop for each drop, then Close to finish the file.
+type Writer interface {
+	WriteHeader(drop.ExportHeader) error
+	WriteDrop(drop.ExportDrop) error
+	Close() error
+}
+
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case FormatHTML:
+		return &htmlWriter{w: w}, nil
+	case FormatOPML:
+		return newOPMLWriter(w), nil
+	case FormatMarkdown:
+		return nil, fmt.Errorf("%w: %s", ErrDirectoryFormat, f)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, f)
+	}
+}
+
+// Copy reads a JSON Lines export stream (as returned by the API) and writes it
+// to w one drop at a time. It returns drop.ErrTruncatedExport if the stream
+// stops early, but the drops before that have already been written by then.
+func Copy(w Writer, r io.Reader) error {
+	dec, err := drop.NewExportDecoder(r)
+	if err != nil {
+		return err
+	}
+	h := dec.Header()
+	if h.Format != drop.ExportFormat || h.Version > drop.ExportVersion {
+		return fmt.Errorf("%w: %s version %d", ErrUnsupportedVersion, h.Format, h.Version)
+	}
+	if err := w.WriteHeader(h); err != nil {
+		return err
+	}
+
+	for {
+		d, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := w.WriteDrop(d); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+type jsonlWriter struct {
+	enc   *json.Encoder
+	count int
+}
+
+func (w *jsonlWriter) WriteHeader(h drop.ExportHeader) error {
+	return w.enc.Encode(h)
+}
+
+func (w *jsonlWriter) WriteDrop(d drop.ExportDrop) error {
+	w.count++
+	return w.enc.Encode(d)
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.enc.Encode(drop.ExportTrailer{End: true, Count: w.count})
+}
@@ -0,0 +1,209 @@
+package exporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/exporter"
+	"github.com/metagram-net/firehose/importer"
+)
+
+func exportStream(t *testing.T, ds ...drop.ExportDrop) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	require.NoError(t, enc.Encode(drop.ExportHeader{
+		Format:     drop.ExportFormat,
+		Version:    drop.ExportVersion,
+		ExportedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+	for _, d := range ds {
+		require.NoError(t, enc.Encode(d))
+	}
+	require.NoError(t, enc.Encode(drop.ExportTrailer{End: true, Count: len(ds)}))
+	return b.String()
+}
+
+func TestRoundTrip(t *testing.T) {
+	movedAt := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	stream := exportStream(t,
+		drop.ExportDrop{ID: "1", Title: "A & B", URL: "https://example.com/a?x=1&y=2", Status: drop.StatusUnread, MovedAt: movedAt, Tags: []string{"go", "web"}},
+		drop.ExportDrop{ID: "2", Title: "C", URL: "https://example.com/c", Status: drop.StatusSaved, MovedAt: movedAt, Tags: []string{}},
+	)
+	want := []drop.BatchItem{
+		{Title: "A & B", URL: "https://example.com/a?x=1&y=2", Status: drop.StatusUnread, MovedAt: &movedAt, Tags: []string{"go", "web"}},
+		{Title: "C", URL: "https://example.com/c", Status: drop.StatusSaved, MovedAt: &movedAt},
+	}
+
+	tests := []struct {
+		export exporter.Format
+		parse  importer.Format
+	}{
+		{exporter.FormatJSONL, importer.FormatFirehose},
+		{exporter.FormatHTML, importer.FormatNetscape},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.export), func(t *testing.T) {
+			var b bytes.Buffer
+			w, err := exporter.NewWriter(&b, tt.export)
+			require.NoError(t, err)
+			require.NoError(t, exporter.Copy(w, strings.NewReader(stream)))
+
+			got, err := importer.Parse(&b, tt.parse)
+			require.NoError(t, err)
+			require.Len(t, got, len(want))
+			for i := range want {
+				assert.Equal(t, want[i].Title, got[i].Title)
+				assert.Equal(t, want[i].URL, got[i].URL)
+				assert.Equal(t, want[i].Status, got[i].Status)
+				assert.ElementsMatch(t, want[i].Tags, got[i].Tags)
+				require.NotNil(t, got[i].MovedAt)
+				assert.True(t, want[i].MovedAt.Equal(*got[i].MovedAt))
+			}
+		})
+	}
+}
+
+func TestOPML(t *testing.T) {
+	movedAt := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	stream := exportStream(t,
+		drop.ExportDrop{ID: "1", Title: "A", URL: "https://example.com/a", Status: drop.StatusRead, MovedAt: movedAt, Tags: []string{"go"}},
+	)
+
+	var b bytes.Buffer
+	w, err := exporter.NewWriter(&b, exporter.FormatOPML)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Copy(w, strings.NewReader(stream)))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<!-- firehose-export version 2 -->
+<opml version="2.0">
+  <head>
+    <title>Firehose</title>
+    <dateCreated>02 Jan 22 03:04 UTC</dateCreated>
+  </head>
+  <body>
+    <outline text="A" type="link" url="https://example.com/a" created="01 Jan 22 12:00 UTC" category="/go" firehoseStatus="read"></outline>
+  </body>
+</opml>
+`, b.String())
+}
+
+func TestMarkdown(t *testing.T) {
+	createdAt := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	stream := exportStream(t,
+		drop.ExportDrop{
+			ID:        "1",
+			Title:     "Paper: A/B Testing",
+			URL:       "https://example.com/paper",
+			Status:    drop.StatusRead,
+			CreatedAt: createdAt,
+			Tags:      []string{"papers"},
+			Notes:     "Worth a second read.",
+			Highlights: []drop.ExportHighlight{
+				{Quote: "First line\nSecond line", Comment: "Good point"},
+				{Quote: "Uncommented"},
+			},
+		},
+		drop.ExportDrop{ID: "2", Title: "paper: a/b testing", URL: "https://example.com/other", Status: drop.StatusUnread, CreatedAt: createdAt},
+		drop.ExportDrop{ID: "3", URL: "https://example.com/untitled", Status: drop.StatusUnread, CreatedAt: createdAt},
+	)
+
+	dir := filepath.Join(t.TempDir(), "vault")
+	require.NoError(t, exporter.Copy(exporter.NewMarkdownWriter(dir), strings.NewReader(stream)))
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	require.NoError(t, err)
+	for i, n := range names {
+		names[i] = filepath.Base(n)
+	}
+	assert.ElementsMatch(t, []string{"Paper A B Testing.md", "paper a b testing (2).md", "3.md"}, names)
+
+	b, err := os.ReadFile(filepath.Join(dir, "Paper A B Testing.md"))
+	require.NoError(t, err)
+	assert.Equal(t, `---
+title: "Paper: A/B Testing"
+url: "https://example.com/paper"
+status: read
+tags: ["papers"]
+created: 2022-01-01T12:00:00Z
+firehose_id: "1"
+---
+
+# Paper: A/B Testing
+
+<https://example.com/paper>
+
+## Notes
+
+Worth a second read.
+
+## Highlights
+
+> First line
+> Second line
+
+Good point
+
+> Uncommented
+`, string(b))
+
+	b, err = os.ReadFile(filepath.Join(dir, "3.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "tags: []\n")
+	assert.Contains(t, string(b), "# https://example.com/untitled\n")
+}
+
+func TestMarkdownNeedsDirectory(t *testing.T) {
+	_, err := exporter.NewWriter(new(bytes.Buffer), exporter.FormatMarkdown)
+	assert.ErrorIs(t, err, exporter.ErrDirectoryFormat)
+}
+
+func TestCopyUnsupportedVersion(t *testing.T) {
+	var b bytes.Buffer
+	w, err := exporter.NewWriter(&b, exporter.FormatJSONL)
+	require.NoError(t, err)
+
+	err = exporter.Copy(w, strings.NewReader(`{"format":"firehose-export","version":999}`))
+	assert.ErrorIs(t, err, exporter.ErrUnsupportedVersion)
+}
+
+func TestCopyTruncated(t *testing.T) {
+	stream := exportStream(t,
+		drop.ExportDrop{ID: "1", Title: "A", URL: "https://example.com/a", Status: drop.StatusUnread},
+		drop.ExportDrop{ID: "2", Title: "B", URL: "https://example.com/b", Status: drop.StatusUnread},
+	)
+	lines := strings.SplitAfter(stream, "\n")
+
+	tests := map[string]string{
+		"no trailer":  strings.Join(lines[:3], ""),
+		"wrong count": lines[0] + lines[1] + `{"end":true,"count":2}` + "\n",
+	}
+	for name, in := range tests {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			w, err := exporter.NewWriter(&b, exporter.FormatJSONL)
+			require.NoError(t, err)
+			assert.ErrorIs(t, exporter.Copy(w, strings.NewReader(in)), drop.ErrTruncatedExport)
+
+			_, err = importer.ParseFirehose(strings.NewReader(in))
+			assert.ErrorIs(t, err, drop.ErrTruncatedExport)
+		})
+	}
+
+	// Version 1 exports didn't have a trailer.
+	v1 := `{"format":"firehose-export","version":1}` + "\n" + lines[1]
+	items, err := importer.ParseFirehose(strings.NewReader(v1))
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+}
@@ -0,0 +1,50 @@
+package exporter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+// htmlWriter writes a Netscape bookmark file, which most browsers and
+// bookmarking services can import. The non-standard FIREHOSE_STATUS attribute
+// lets Firehose's own importer restore statuses that TOREAD can't express.
+type htmlWriter struct {
+	w io.Writer
+}
+
+func (w *htmlWriter) WriteHeader(h drop.ExportHeader) error {
+	_, err := fmt.Fprintf(w.w, `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- %s version %d -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Firehose</TITLE>
+<H1>Firehose</H1>
+<DL><p>
+`, h.Format, h.Version)
+	return err
+}
+
+func (w *htmlWriter) WriteDrop(d drop.ExportDrop) error {
+	toread := "0"
+	if d.Status == drop.StatusUnread {
+		toread = "1"
+	}
+	_, err := fmt.Fprintf(w.w,
+		"    <DT><A HREF=\"%s\" ADD_DATE=\"%d\" TAGS=\"%s\" TOREAD=\"%s\" FIREHOSE_STATUS=\"%s\">%s</A>\n",
+		html.EscapeString(d.URL),
+		d.MovedAt.Unix(),
+		html.EscapeString(strings.Join(d.Tags, ",")),
+		toread,
+		d.Status,
+		html.EscapeString(d.Title),
+	)
+	return err
+}
+
+func (w *htmlWriter) Close() error {
+	_, err := io.WriteString(w.w, "</DL><p>\n")
+	return err
+}
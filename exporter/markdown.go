@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+// maxNameLength limits the length of Markdown file names (in characters, not
+// counting the extension). Most file systems allow 255 bytes.
+const maxNameLength = 100
+
+type markdownWriter struct {
+	dir   string
+	names map[string]bool
+}
+
+// NewMarkdownWriter creates a Writer that saves each drop as its own Markdown
+// file in dir, which is the layout note-taking apps like Obsidian expect for a
+// vault. Files are named after the drop titles. Existing files with the same
+// names are overwritten, so exporting again updates the notes in place.
+func NewMarkdownWriter(dir string) Writer {
+	return &markdownWriter{dir: dir, names: make(map[string]bool)}
+}
+
+func (w *markdownWriter) WriteHeader(drop.ExportHeader) error {
+	return os.MkdirAll(w.dir, 0o755)
+}
+
+func (w *markdownWriter) WriteDrop(d drop.ExportDrop) error {
+	f, err := os.Create(filepath.Join(w.dir, w.fileName(d)))
+	if err != nil {
+		return err
+	}
+	if err := writeMarkdown(f, d); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (*markdownWriter) Close() error {
+	return nil
+}
+
+// fileName picks a name for the drop's file that's safe on common file systems
+// and hasn't been used yet in this export.
+func (w *markdownWriter) fileName(d drop.ExportDrop) string {
+	base := strings.Map(func(r rune) rune {
+		// Besides what file systems forbid, avoid the characters that break
+		// wiki-style links.
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|#^[]`, r) {
+			return ' '
+		}
+		return r
+	}, d.Title)
+	base = strings.Join(strings.Fields(base), " ")
+	if utf8.RuneCountInString(base) > maxNameLength {
+		base = strings.TrimSpace(string([]rune(base)[:maxNameLength]))
+	}
+	// Leading dots hide files, and trailing dots confuse Windows.
+	base = strings.Trim(base, ". ")
+	if base == "" {
+		base = d.ID
+	}
+
+	// Case-insensitive file systems would treat "Go" and "go" as one file.
+	name := base
+	for n := 2; w.names[strings.ToLower(name)]; n++ {
+		name = fmt.Sprintf("%s (%d)", base, n)
+	}
+	w.names[strings.ToLower(name)] = true
+	return name + ".md"
+}
+
+// writeMarkdown writes a drop as a Markdown note with YAML front matter.
+func writeMarkdown(out io.Writer, d drop.ExportDrop) error {
+	w := bufio.NewWriter(out)
+
+	title := d.Title
+	if title == "" {
+		title = d.URL
+	}
+
+	// JSON strings are valid YAML, and quoting them all saves worrying about
+	// which characters YAML treats specially.
+	tags := d.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	fmt.Fprintln(w, "---")
+	fmt.Fprintf(w, "title: %s\n", quote(title))
+	fmt.Fprintf(w, "url: %s\n", quote(d.URL))
+	fmt.Fprintf(w, "status: %s\n", d.Status)
+	fmt.Fprintf(w, "tags: %s\n", quote(tags))
+	fmt.Fprintf(w, "created: %s\n", d.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "firehose_id: %s\n", quote(d.ID))
+	fmt.Fprintln(w, "---")
+
+	fmt.Fprintf(w, "\n# %s\n\n<%s>\n", title, d.URL)
+
+	if notes := strings.TrimSpace(d.Notes); notes != "" {
+		fmt.Fprintf(w, "\n## Notes\n\n%s\n", notes)
+	}
+
+	if len(d.Highlights) > 0 {
+		fmt.Fprint(w, "\n## Highlights\n")
+		for _, h := range d.Highlights {
+			fmt.Fprintln(w)
+			for _, line := range strings.Split(strings.TrimSpace(h.Quote), "\n") {
+				fmt.Fprintln(w, strings.TrimRight("> "+line, " "))
+			}
+			if c := strings.TrimSpace(h.Comment); c != "" {
+				fmt.Fprintf(w, "\n%s\n", c)
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+func quote(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// Strings and string slices always marshal.
+		panic(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,101 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+// opmlWriter writes an OPML 2.0 outline with one link outline per drop. Tags
+// are written as categories, which OPML spells as slash-prefixed paths.
+type opmlWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newOPMLWriter(w io.Writer) *opmlWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &opmlWriter{w: w, enc: enc}
+}
+
+type opmlHead struct {
+	XMLName     xml.Name `xml:"head"`
+	Title       string   `xml:"title"`
+	DateCreated string   `xml:"dateCreated"`
+}
+
+type opmlOutline struct {
+	XMLName  xml.Name `xml:"outline"`
+	Text     string   `xml:"text,attr"`
+	Type     string   `xml:"type,attr"`
+	URL      string   `xml:"url,attr"`
+	Created  string   `xml:"created,attr"`
+	Category string   `xml:"category,attr,omitempty"`
+	Status   string   `xml:"firehoseStatus,attr"`
+}
+
+var (
+	opmlRoot = xml.StartElement{
+		Name: xml.Name{Local: "opml"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "version"}, Value: "2.0"}},
+	}
+	opmlBody = xml.StartElement{Name: xml.Name{Local: "body"}}
+)
+
+func (w *opmlWriter) WriteHeader(h drop.ExportHeader) error {
+	// The encoder doesn't indent after top-level comments, so write the
+	// prologue directly.
+	_, err := fmt.Fprintf(w.w, "%s<!-- %s version %d -->\n", xml.Header, h.Format, h.Version)
+	if err != nil {
+		return err
+	}
+	if err := w.enc.EncodeToken(opmlRoot); err != nil {
+		return err
+	}
+	err = w.enc.Encode(opmlHead{
+		Title:       "Firehose",
+		DateCreated: h.ExportedAt.Format(time.RFC822),
+	})
+	if err != nil {
+		return err
+	}
+	return w.enc.EncodeToken(opmlBody)
+}
+
+func (w *opmlWriter) WriteDrop(d drop.ExportDrop) error {
+	var cats []string
+	for _, t := range d.Tags {
+		cats = append(cats, "/"+t)
+	}
+	text := d.Title
+	if text == "" {
+		text = d.URL
+	}
+	return w.enc.Encode(opmlOutline{
+		Text:     text,
+		Type:     "link",
+		URL:      d.URL,
+		Created:  d.MovedAt.Format(time.RFC822),
+		Category: strings.Join(cats, ","),
+		Status:   d.Status.String(),
+	})
+}
+
+func (w *opmlWriter) Close() error {
+	if err := w.enc.EncodeToken(opmlBody.End()); err != nil {
+		return err
+	}
+	if err := w.enc.EncodeToken(opmlRoot.End()); err != nil {
+		return err
+	}
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "\n")
+	return err
+}
@@ -20,4 +20,5 @@ require (
 	github.com/spf13/viper v1.10.0
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/zap v1.19.1
+	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2
 )
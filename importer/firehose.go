@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported export version")
+
+// ParseFirehose reads a Firehose JSON Lines export: a drop.ExportHeader line
+// followed by one drop.ExportDrop per line and, since version 2, a
+// drop.ExportTrailer. Exports without their trailer are incomplete, so this
+// returns drop.ErrTruncatedExport instead of importing part of one.
+func ParseFirehose(r io.Reader) ([]drop.BatchItem, error) {
+	dec, err := drop.NewExportDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	h := dec.Header()
+	if h.Format != drop.ExportFormat || h.Version < 1 || h.Version > drop.ExportVersion {
+		return nil, fmt.Errorf("%w: %s version %d", ErrUnsupportedVersion, h.Format, h.Version)
+	}
+
+	items := make([]drop.BatchItem, 0)
+	for {
+		d, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var hs []drop.BatchHighlight
+		for _, h := range d.Highlights {
+			hs = append(hs, drop.BatchHighlight{
+				Quote:     h.Quote,
+				Comment:   h.Comment,
+				CreatedAt: timeOrNil(h.CreatedAt),
+			})
+		}
+		items = append(items, drop.BatchItem{
+			Title:       d.Title,
+			URL:         d.URL,
+			Status:      d.Status,
+			MovedAt:     timeOrNil(d.MovedAt),
+			CreatedAt:   timeOrNil(d.CreatedAt),
+			Tags:        d.Tags,
+			SnoozeUntil: d.SnoozeUntil,
+			PinnedAt:    d.PinnedAt,
+			Notes:       d.Notes,
+			Highlights:  hs,
+		})
+	}
+}
+
+// timeOrNil returns nil for the zero time, which older exports leave in place
+// of fields they didn't have yet.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
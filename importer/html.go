@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+// ParsePocket reads a Pocket export file. Pocket exports are HTML with one
+// list of links per section, where the "Read Archive" section holds the links
+// that were marked as read.
+//
+//	<h1>Unread</h1>
+//	<ul>
+//	  <li><a href="https://example.com" time_added="1641081600" tags="go,web">Example</a></li>
+//	</ul>
+//	<h1>Read Archive</h1>
+//	<ul>...</ul>
+func ParsePocket(r io.Reader) ([]drop.BatchItem, error) {
+	items := make([]drop.BatchItem, 0)
+	status := drop.StatusUnread
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return items, nil
+			}
+			return nil, z.Err()
+
+		case html.StartTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "h1":
+				if strings.Contains(strings.ToLower(text(z, "h1")), "read archive") {
+					status = drop.StatusRead
+				} else {
+					status = drop.StatusUnread
+				}
+			case "a":
+				items = append(items, drop.BatchItem{
+					URL:     attr(t, "href"),
+					Title:   text(z, "a"),
+					Status:  status,
+					MovedAt: unixAttr(t, "time_added"),
+					Tags:    splitTags(attr(t, "tags")),
+				})
+			}
+		}
+	}
+}
+
+// ParseNetscape reads a Netscape bookmark file, the format most browsers and
+// bookmarking services export. Each folder a bookmark is in becomes a tag, in
+// addition to any tags listed on the bookmark itself. Bookmarks with
+// TOREAD="0" are imported as read, and all others as unread, unless the file
+// came from a Firehose export and has the exact status in FIREHOSE_STATUS.
+//
+//	<!DOCTYPE NETSCAPE-Bookmark-file-1>
+//	<DL><p>
+//	  <DT><H3>Folder</H3>
+//	  <DL><p>
+//	    <DT><A HREF="https://example.com" ADD_DATE="1641081600" TAGS="go,web">Example</A>
+//	  </DL><p>
+//	</DL><p>
+func ParseNetscape(r io.Reader) ([]drop.BatchItem, error) {
+	items := make([]drop.BatchItem, 0)
+
+	// Each <DL> opens a list of bookmarks inside the folder named by the
+	// preceding <H3>. The outermost list has no folder.
+	var folders []string
+	var folder string
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return items, nil
+			}
+			return nil, z.Err()
+
+		case html.StartTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "h3":
+				folder = strings.TrimSpace(text(z, "h3"))
+			case "dl":
+				folders = append(folders, folder)
+				folder = ""
+			case "a":
+				var tags []string
+				for _, f := range folders {
+					if f != "" {
+						tags = append(tags, f)
+					}
+				}
+				tags = append(tags, splitTags(attr(t, "tags"))...)
+
+				status := drop.StatusUnread
+				if attr(t, "toread") == "0" {
+					status = drop.StatusRead
+				}
+				if s, err := drop.StatusString(attr(t, "firehose_status")); err == nil && s != drop.StatusUnknown {
+					status = s
+				}
+
+				items = append(items, drop.BatchItem{
+					URL:     attr(t, "href"),
+					Title:   text(z, "a"),
+					Status:  status,
+					MovedAt: unixAttr(t, "add_date"),
+					Tags:    tags,
+				})
+			}
+
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "dl" && len(folders) > 0 {
+				folders = folders[:len(folders)-1]
+			}
+		}
+	}
+}
+
+// attr returns the value of the named attribute, or the empty string if the
+// token doesn't have it. The tokenizer lowercases attribute names.
+func attr(t html.Token, name string) string {
+	for _, a := range t.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// unixAttr parses the named attribute as a Unix timestamp in seconds. It
+// returns nil if the attribute is missing or invalid.
+func unixAttr(t html.Token, name string) *time.Time {
+	n, err := strconv.ParseInt(attr(t, name), 10, 64)
+	if err != nil || n <= 0 {
+		return nil
+	}
+	ts := time.Unix(n, 0).UTC()
+	return &ts
+}
+
+// text reads and concatenates text tokens until the closing tag.
+func text(z *html.Tokenizer, tag string) string {
+	var b strings.Builder
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == tag {
+				return strings.TrimSpace(b.String())
+			}
+		}
+	}
+}
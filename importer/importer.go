@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrDetectFormat  = errors.New("could not detect import format")
+)
+
+type Format string
+
+const (
+	FormatPocket     Format = "pocket"
+	FormatInstapaper Format = "instapaper"
+	FormatNetscape   Format = "netscape"
+	FormatMarkdown   Format = "markdown"
+	FormatFirehose   Format = "firehose"
+)
+
+// FormatValueStrings returns all valid values of the enum as strings.
+func FormatValueStrings() []string {
+	return []string{
+		string(FormatPocket),
+		string(FormatInstapaper),
+		string(FormatNetscape),
+		string(FormatMarkdown),
+		string(FormatFirehose),
+	}
+}
+
+// Implement pflag.Value
+
+func (f *Format) String() string {
+	return string(*f)
+}
+
+func (f *Format) Set(s string) error {
+	for _, v := range FormatValueStrings() {
+		if s == v {
+			*f = Format(s)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s (expected one of: %s)", ErrUnknownFormat, s, strings.Join(FormatValueStrings(), ", "))
+}
+
+func (*Format) Type() string {
+	return "importFormat"
+}
+
+// Detect guesses the format of an export file from its name and the first few
+// bytes of its contents.
+func Detect(filename string, head []byte) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatInstapaper, nil
+	case ".md", ".markdown", ".txt":
+		return FormatMarkdown, nil
+	case ".jsonl", ".ndjson":
+		return FormatFirehose, nil
+	}
+
+	lower := bytes.ToLower(head)
+	switch {
+	case bytes.Contains(lower, []byte(drop.ExportFormat)):
+		return FormatFirehose, nil
+	case bytes.Contains(lower, []byte("netscape-bookmark-file")):
+		return FormatNetscape, nil
+	case bytes.Contains(lower, []byte("<title>pocket export</title>")):
+		return FormatPocket, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrDetectFormat, filename)
+}
+
+// Parse reads all the links in an export file.
+func Parse(r io.Reader, f Format) ([]drop.BatchItem, error) {
+	switch f {
+	case FormatPocket:
+		return ParsePocket(r)
+	case FormatInstapaper:
+		return ParseInstapaper(r)
+	case FormatNetscape:
+		return ParseNetscape(r)
+	case FormatMarkdown:
+		return ParseMarkdown(r)
+	case FormatFirehose:
+		return ParseFirehose(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, f)
+	}
+}
+
+// splitTags splits a comma-separated list of tags, dropping blanks.
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
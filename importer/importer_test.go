@@ -0,0 +1,144 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/importer"
+)
+
+func ts(sec int64) *time.Time {
+	t := time.Unix(sec, 0).UTC()
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format importer.Format
+		input  string
+		want   []drop.BatchItem
+	}{
+		{
+			name:   "pocket",
+			format: importer.FormatPocket,
+			input: `<!DOCTYPE html>
+<html><head><title>Pocket Export</title></head><body>
+<h1>Unread</h1>
+<ul>
+<li><a href="https://example.com/a" time_added="1641081600" tags="go,web">Article &amp; A</a></li>
+</ul>
+<h1>Read Archive</h1>
+<ul>
+<li><a href="https://example.com/b" time_added="1641168000" tags="">B</a></li>
+</ul>
+</body></html>`,
+			want: []drop.BatchItem{
+				{URL: "https://example.com/a", Title: "Article & A", Status: drop.StatusUnread, MovedAt: ts(1641081600), Tags: []string{"go", "web"}},
+				{URL: "https://example.com/b", Title: "B", Status: drop.StatusRead, MovedAt: ts(1641168000)},
+			},
+		},
+		{
+			name:   "instapaper",
+			format: importer.FormatInstapaper,
+			input: `URL,Title,Selection,Folder,Timestamp
+https://example.com/a,"A, with comma",,Unread,1641081600
+https://example.com/b,B,,Archive,1641168000
+https://example.com/c,C,,Starred,
+https://example.com/d,D,,Papers,1641254400
+`,
+			want: []drop.BatchItem{
+				{URL: "https://example.com/a", Title: "A, with comma", Status: drop.StatusUnread, MovedAt: ts(1641081600)},
+				{URL: "https://example.com/b", Title: "B", Status: drop.StatusRead, MovedAt: ts(1641168000)},
+				{URL: "https://example.com/c", Title: "C", Status: drop.StatusSaved},
+				{URL: "https://example.com/d", Title: "D", Status: drop.StatusUnread, MovedAt: ts(1641254400), Tags: []string{"Papers"}},
+			},
+		},
+		{
+			name:   "netscape",
+			format: importer.FormatNetscape,
+			input: `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><A HREF="https://example.com/a" ADD_DATE="1641081600" TOREAD="1">A</A>
+    <DT><H3 ADD_DATE="1641081600">Programming</H3>
+    <DL><p>
+        <DT><H3>Go</H3>
+        <DL><p>
+            <DT><A HREF="https://example.com/b" ADD_DATE="1641168000" TAGS="web">B</A>
+        </DL><p>
+        <DT><A HREF="https://example.com/c" TOREAD="0">C</A>
+    </DL><p>
+</DL><p>`,
+			want: []drop.BatchItem{
+				{URL: "https://example.com/a", Title: "A", Status: drop.StatusUnread, MovedAt: ts(1641081600)},
+				{URL: "https://example.com/b", Title: "B", Status: drop.StatusUnread, MovedAt: ts(1641168000), Tags: []string{"Programming", "Go", "web"}},
+				{URL: "https://example.com/c", Title: "C", Status: drop.StatusRead, Tags: []string{"Programming"}},
+			},
+		},
+		{
+			name:   "markdown",
+			format: importer.FormatMarkdown,
+			input: `# Reading
+
+Some intro text without links.
+
+- [A](https://example.com/a)
+* [x] https://example.com/b.
+
+## Papers
+
+1. [ ] [C](<https://example.com/c> "title")
+2. <https://example.com/d>
+
+# Videos
+- not a link
+- [E](https://example.com/e)
+`,
+			want: []drop.BatchItem{
+				{URL: "https://example.com/a", Title: "A", Status: drop.StatusUnread, Tags: []string{"Reading"}},
+				{URL: "https://example.com/b", Status: drop.StatusRead, Tags: []string{"Reading"}},
+				{URL: "https://example.com/c", Title: "C", Status: drop.StatusUnread, Tags: []string{"Reading", "Papers"}},
+				{URL: "https://example.com/d", Status: drop.StatusUnread, Tags: []string{"Reading", "Papers"}},
+				{URL: "https://example.com/e", Title: "E", Status: drop.StatusUnread, Tags: []string{"Videos"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importer.Parse(strings.NewReader(tt.input), tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		filename string
+		head     string
+		want     importer.Format
+	}{
+		{"instapaper-export.csv", "URL,Title", importer.FormatInstapaper},
+		{"links.md", "# Links", importer.FormatMarkdown},
+		{"bookmarks.html", "<!DOCTYPE NETSCAPE-Bookmark-file-1>", importer.FormatNetscape},
+		{"ril_export.html", "<html><head><title>Pocket Export</title>", importer.FormatPocket},
+	}
+	for _, tt := range tests {
+		got, err := importer.Detect(tt.filename, []byte(tt.head))
+		require.NoError(t, err, tt.filename)
+		assert.Equal(t, tt.want, got, tt.filename)
+	}
+
+	_, err := importer.Detect("unknown.html", []byte("<html>"))
+	assert.ErrorIs(t, err, importer.ErrDetectFormat)
+}
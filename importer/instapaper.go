@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+var ErrMissingColumn = errors.New("missing CSV column")
+
+// ParseInstapaper reads an Instapaper CSV export. The built-in folders map to
+// statuses (Archive is read, Starred is saved) and any other folder becomes a
+// tag on an unread drop.
+//
+//	URL,Title,Selection,Folder,Timestamp
+//	https://example.com,Example,,Unread,1641081600
+func ParseInstapaper(r io.Reader) ([]drop.BatchItem, error) {
+	cr := csv.NewReader(r)
+	// Older exports don't have the Tags column, so don't insist on a fixed
+	// number of fields.
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return make([]drop.BatchItem, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]int)
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := cols["url"]; !ok {
+		return nil, fmt.Errorf("%w: URL", ErrMissingColumn)
+	}
+
+	items := make([]drop.BatchItem, 0)
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(col string) string {
+			i, ok := cols[col]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[i])
+		}
+
+		it := drop.BatchItem{
+			URL:    get("url"),
+			Title:  get("title"),
+			Status: drop.StatusUnread,
+			Tags:   instapaperTags(get("tags")),
+		}
+
+		switch folder := get("folder"); strings.ToLower(folder) {
+		case "", "unread":
+		case "archive":
+			it.Status = drop.StatusRead
+		case "starred":
+			it.Status = drop.StatusSaved
+		default:
+			it.Tags = append([]string{folder}, it.Tags...)
+		}
+
+		if n, err := strconv.ParseInt(get("timestamp"), 10, 64); err == nil && n > 0 {
+			ts := time.Unix(n, 0).UTC()
+			it.MovedAt = &ts
+		}
+
+		items = append(items, it)
+	}
+}
+
+// instapaperTags parses the Tags column, which newer exports write as a JSON
+// array of strings.
+func instapaperTags(s string) []string {
+	if !strings.HasPrefix(s, "[") {
+		return splitTags(s)
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(s), &tags); err != nil {
+		return splitTags(strings.Trim(s, "[]"))
+	}
+	return tags
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/metagram-net/firehose/drop"
+)
+
+var (
+	mdHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.*)$`)
+	mdLink     = regexp.MustCompile(`\[([^\]]*)\]\(\s*<?(https?://[^\s)>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdAutolink = regexp.MustCompile(`<(https?://[^\s>]+)>`)
+	mdBareURL  = regexp.MustCompile(`https?://[^\s<>()]+`)
+)
+
+// ParseMarkdown reads a Markdown document of links, one per line. The headings
+// a link is under become its tags, and checked task list items are imported as
+// read.
+//
+//	# Reading
+//	## Go
+//	- [Effective Go](https://go.dev/doc/effective_go)
+//	- [x] https://go.dev/blog/
+func ParseMarkdown(r io.Reader) ([]drop.BatchItem, error) {
+	items := make([]drop.BatchItem, 0)
+
+	// headings[i] is the current heading at level i+1.
+	var headings []string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			level := len(m[1])
+			for len(headings) < level {
+				headings = append(headings, "")
+			}
+			headings = append(headings[:level-1], m[2])
+			continue
+		}
+
+		status := drop.StatusUnread
+		if m := mdListItem.FindStringSubmatch(line); m != nil {
+			if strings.EqualFold(m[1], "x") {
+				status = drop.StatusRead
+			}
+			line = m[2]
+		}
+
+		title, url := mdFindLink(line)
+		if url == "" {
+			continue
+		}
+
+		var tags []string
+		for _, h := range headings {
+			if h != "" {
+				tags = append(tags, h)
+			}
+		}
+
+		items = append(items, drop.BatchItem{
+			URL:    url,
+			Title:  title,
+			Status: status,
+			Tags:   tags,
+		})
+	}
+	return items, s.Err()
+}
+
+// mdFindLink returns the first link in the line. Inline links use their text as
+// the title, and bare URLs have no title.
+func mdFindLink(line string) (title, url string) {
+	if m := mdLink.FindStringSubmatch(line); m != nil {
+		return strings.TrimSpace(m[1]), m[2]
+	}
+	if m := mdAutolink.FindStringSubmatch(line); m != nil {
+		return "", m[1]
+	}
+	if m := mdBareURL.FindString(line); m != "" {
+		return "", strings.TrimRight(m, ".,;:!?")
+	}
+	return "", ""
+}
@@ -0,0 +1,175 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrUnsupportedScheme = errors.New("only http and https URLs can be fetched")
+	ErrPrivateAddress    = errors.New("refusing to connect to a private address")
+	ErrHTTPStatus        = errors.New("unsuccessful response status")
+	ErrNotHTML           = errors.New("response is not HTML")
+)
+
+// maxBodySize limits how much of a page is read. Metadata is in the <head>, so
+// this only matters for pages that never close it.
+const maxBodySize = 1 << 20
+
+const userAgent = "Firehose (+https://github.com/metagram-net/firehose)"
+
+// A Fetcher retrieves pages and parses their metadata.
+type Fetcher struct {
+	client *http.Client
+}
+
+// NewFetcher creates a Fetcher that refuses to connect to loopback, private,
+// and other non-public addresses. The URLs come from users, so without this
+// anyone could make the server send requests into its own network.
+//
+// Set allowPrivate to skip that check, which is only safe in tests or on a
+// server with no private network to protect.
+func NewFetcher(allowPrivate bool) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+	}
+	if !allowPrivate {
+		// Check the address being dialed rather than the hostname in the URL.
+		// That way the check can't be dodged by DNS tricks or redirects.
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		}
+	}
+
+	return &Fetcher{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				// A proxy would connect on our behalf, so always dial directly.
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+	}
+}
+
+// Fetch retrieves the page at rawURL and parses its metadata. Redirects are
+// followed, and relative image URLs resolve against the final page URL.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Metadata, error) {
+	body, u, err := f.Open(ctx, rawURL)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer body.Close()
+
+	return Parse(io.LimitReader(body, maxBodySize), u)
+}
+
+// Open retrieves the HTML page at rawURL, following redirects. It returns the
+// response body, which the caller must close, and the final page URL.
+func (f *Fetcher) Open(ctx context.Context, rawURL string) (io.ReadCloser, *url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, nil, ErrUnsupportedScheme
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		res.Body.Close()
+		return nil, nil, fmt.Errorf("%w: %s", ErrHTTPStatus, res.Status)
+	}
+	if !isHTML(res.Header.Get("Content-Type")) {
+		res.Body.Close()
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotHTML, res.Header.Get("Content-Type"))
+	}
+
+	return res.Body, res.Request.URL, nil
+}
+
+func isHTML(contentType string) bool {
+	// Some servers don't bother with a content type, so give them the benefit
+	// of the doubt.
+	if contentType == "" {
+		return true
+	}
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return t == "text/html" || t == "application/xhtml+xml"
+}
+
+// privateNets are the address ranges that aren't reachable on the public
+// internet: loopback, private networks, link-local, carrier-grade NAT,
+// benchmarking, multicast, and reserved ranges.
+var privateNets = mustCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func isPrivate(ip net.IP) bool {
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
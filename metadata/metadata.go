@@ -0,0 +1,127 @@
+// Package metadata reads the title and preview fields of web pages.
+package metadata
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Metadata is the preview information a page declares about itself.
+type Metadata struct {
+	Title       string
+	Description string
+	SiteName    string
+	ImageURL    string
+}
+
+// Parse reads the metadata from the <head> of an HTML document. OpenGraph
+// fields are preferred, then Twitter card fields, then plain HTML elements.
+// Relative image URLs are resolved against base.
+func Parse(r io.Reader, base *url.URL) (Metadata, error) {
+	var (
+		title string
+		meta  = make(map[string]string)
+	)
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				break loop
+			}
+			return Metadata{}, z.Err()
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "title":
+				if title == "" {
+					title = text(z)
+				}
+			case "meta":
+				// OpenGraph uses "property" and everything else uses "name",
+				// but plenty of pages mix them up.
+				key := attr(t, "property")
+				if key == "" {
+					key = attr(t, "name")
+				}
+				key = strings.ToLower(key)
+				if _, ok := meta[key]; !ok && key != "" {
+					meta[key] = attr(t, "content")
+				}
+			case "body":
+				// Metadata only belongs in the head, so don't bother reading
+				// the rest of the page.
+				break loop
+			}
+
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "head" {
+				break loop
+			}
+		}
+	}
+
+	m := Metadata{
+		Title:       first(meta["og:title"], meta["twitter:title"], title),
+		Description: first(meta["og:description"], meta["twitter:description"], meta["description"]),
+		SiteName:    first(meta["og:site_name"], meta["application-name"]),
+		ImageURL:    first(meta["og:image"], meta["og:image:url"], meta["twitter:image"], meta["twitter:image:src"]),
+	}
+	if m.ImageURL != "" {
+		m.ImageURL = resolve(base, m.ImageURL)
+	}
+	return m, nil
+}
+
+// attr returns the value of the named attribute, or the empty string if the
+// token doesn't have it.
+func attr(t html.Token, name string) string {
+	for _, a := range t.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// text reads the text of the current element. Only <title> uses this, and its
+// contents are always plain text.
+func text(z *html.Tokenizer) string {
+	if z.Next() != html.TextToken {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(z.Text())), " ")
+}
+
+// first returns the first non-blank value.
+func first(vals ...string) string {
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// resolve makes ref absolute relative to base. Only http(s) URLs are useful to
+// clients, so anything else (like data: URLs) is dropped.
+func resolve(base *url.URL, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
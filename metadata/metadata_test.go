@@ -0,0 +1,140 @@
+package metadata_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/metadata"
+)
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+  <title>
+    Plain   Title
+  </title>
+  <meta name="description" content="Plain description">
+  <meta name="twitter:title" content="Twitter Title">
+  <meta property="og:description" content="OpenGraph description">
+  <meta property="og:site_name" content="Example">
+  <meta property="og:image" content="/preview.png">
+</head>
+<body>
+  <meta property="og:title" content="Not in the head">
+</body>
+</html>`
+
+func TestParse(t *testing.T) {
+	base, err := url.Parse("https://example.com/posts/1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		html string
+		want metadata.Metadata
+	}{
+		{
+			name: "precedence",
+			html: page,
+			want: metadata.Metadata{
+				Title:       "Twitter Title",
+				Description: "OpenGraph description",
+				SiteName:    "Example",
+				ImageURL:    "https://example.com/preview.png",
+			},
+		},
+		{
+			name: "title only",
+			html: `<html><head><title>Just a title</title></head></html>`,
+			want: metadata.Metadata{Title: "Just a title"},
+		},
+		{
+			name: "data image",
+			html: `<meta property="og:image" content="data:image/png;base64,AAAA">`,
+			want: metadata.Metadata{},
+		},
+		{
+			name: "empty",
+			html: ``,
+			want: metadata.Metadata{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := metadata.Parse(strings.NewReader(tt.html), base)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	ctx := apitest.Context(t, time.Second)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	f := metadata.NewFetcher(true)
+
+	t.Run("page", func(t *testing.T) {
+		m, err := f.Fetch(ctx, srv.URL+"/page")
+		require.NoError(t, err)
+		assert.Equal(t, "Twitter Title", m.Title)
+		assert.Equal(t, srv.URL+"/preview.png", m.ImageURL)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		m, err := f.Fetch(ctx, srv.URL+"/redirect")
+		require.NoError(t, err)
+		assert.Equal(t, "Twitter Title", m.Title)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := f.Fetch(ctx, srv.URL+"/missing")
+		assert.ErrorIs(t, err, metadata.ErrHTTPStatus)
+	})
+
+	t.Run("not html", func(t *testing.T) {
+		_, err := f.Fetch(ctx, srv.URL+"/image")
+		assert.ErrorIs(t, err, metadata.ErrNotHTML)
+	})
+
+	t.Run("scheme", func(t *testing.T) {
+		_, err := f.Fetch(ctx, "file:///etc/passwd")
+		assert.ErrorIs(t, err, metadata.ErrUnsupportedScheme)
+	})
+}
+
+func TestFetchPrivateAddress(t *testing.T) {
+	ctx := apitest.Context(t, time.Second)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("The fetcher should not have connected")
+	}))
+	t.Cleanup(srv.Close)
+
+	f := metadata.NewFetcher(false)
+	_, err := f.Fetch(ctx, srv.URL)
+	assert.ErrorIs(t, err, metadata.ErrPrivateAddress)
+}
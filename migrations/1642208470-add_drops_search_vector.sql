@@ -0,0 +1,24 @@
+select require_migration(1637448737);
+
+alter table drops add column search_vector tsvector not null default ''::tsvector;
+
+-- Titles are the best signal, but URLs often contain the slug of an article
+-- title too. Split URLs on punctuation so the path segments become words.
+create function drops_search_vector() returns trigger as $$
+begin
+    new.search_vector :=
+        setweight(to_tsvector('english', coalesce(new.title, '')), 'A') ||
+        setweight(to_tsvector('english', regexp_replace(new.url, '[^[:alnum:]]+', ' ', 'g')), 'B');
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger set_search_vector before insert or update of title, url on drops
+    for each row execute procedure drops_search_vector();
+
+-- Backfill existing drops without bumping their updated_at timestamps.
+alter table drops disable trigger set_updated_at;
+update drops set title = title;
+alter table drops enable trigger set_updated_at;
+
+create index on drops using gin (search_vector);
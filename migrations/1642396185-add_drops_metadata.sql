@@ -0,0 +1,11 @@
+select require_migration(1642208470);
+
+alter table drops
+    add column description text,
+    add column site_name text,
+    add column image_url text,
+    add column metadata_fetched_at timestamp;
+
+-- The metadata fetcher only looks at untitled drops it hasn't tried yet.
+create index on drops (created_at)
+    where metadata_fetched_at is null and coalesce(title, '') = '';
@@ -0,0 +1,17 @@
+select require_migration(1642396185);
+
+alter table drops add column canonical_url text;
+
+-- Canonical URLs are computed by the server, so existing drops are matched on
+-- their exact URLs. If a user already has exact duplicates, only the oldest
+-- one gets a canonical URL.
+alter table drops disable trigger set_updated_at;
+update drops set canonical_url = url
+where id in (
+    select distinct on (user_id, url) id
+    from drops
+    order by user_id, url, created_at asc, id asc
+);
+alter table drops enable trigger set_updated_at;
+
+create unique index on drops (user_id, canonical_url);
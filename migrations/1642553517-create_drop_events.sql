@@ -0,0 +1,20 @@
+select require_migration(1642470553);
+
+create type drop_event_kind as enum ('create', 'update', 'tag', 'move', 'delete');
+
+-- Events outlive their drops, so drop_id is deliberately not a foreign key.
+-- The before and after columns are snapshots of the drop as the API rendered
+-- it, or JSON null if the drop didn't exist.
+create table drop_events (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id),
+    drop_id uuid not null,
+
+    kind drop_event_kind not null,
+    before jsonb not null,
+    after jsonb not null,
+
+    -- Use the wall clock so events in the same transaction stay in order.
+    created_at timestamp not null default clock_timestamp()
+);
+create index on drop_events (user_id, drop_id, created_at);
@@ -0,0 +1,6 @@
+select require_migration(1642553517);
+
+-- Snoozed drops have their moved_at set to snooze_until, so they take their
+-- place in the unread queue as if they had been dropped when they wake up.
+alter type drop_status add value 'snoozed';
+alter table drops add column snooze_until timestamp;
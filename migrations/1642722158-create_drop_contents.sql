@@ -0,0 +1,19 @@
+select require_migration(1642637212);
+
+-- A snapshot of the readable content of each drop's page, so it's still there
+-- after the page changes or disappears. Failed fetches are saved too, with the
+-- reason, so they don't hold up the queue.
+create table drop_contents (
+    drop_id uuid primary key references drops(id) on delete cascade,
+    user_id uuid not null references users(id),
+
+    -- The page URL after redirects.
+    url text not null,
+    title text not null,
+    text text not null,
+    html text not null,
+    error text,
+
+    fetched_at timestamp not null
+);
+create index on drop_contents (user_id);
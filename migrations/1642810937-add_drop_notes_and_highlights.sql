@@ -0,0 +1,17 @@
+select require_migration(1642722158);
+
+alter table drops add column notes text;
+
+create table drop_highlights (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id),
+    drop_id uuid not null references drops(id) on delete cascade,
+
+    quote text not null,
+    comment text,
+
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp
+);
+create index on drop_highlights (user_id, drop_id, created_at);
+select manage_updated_at('drop_highlights');
@@ -0,0 +1,17 @@
+select require_migration(1642810937);
+
+-- Deleted drops stay in the trash, hidden from everything but the trash
+-- routes, until they're restored or purged.
+alter table drops add column deleted_at timestamp;
+create index on drops (deleted_at) where deleted_at is not null;
+
+-- A trashed drop shouldn't block dropping the same URL again.
+drop index drops_user_id_canonical_url_idx;
+create unique index on drops (user_id, canonical_url) where deleted_at is null;
+
+-- Purging the trash deletes drops in bulk, so let their tags go with them.
+alter table drop_tags drop constraint drop_tags_drop_id_fkey;
+alter table drop_tags add constraint drop_tags_drop_id_fkey
+    foreign key (drop_id) references drops(id) on delete cascade;
+
+alter type drop_event_kind add value 'restore';
@@ -0,0 +1,20 @@
+select require_migration(1642897337);
+
+-- Both are computed from the readable content, so they stay null until that
+-- has been fetched (or if it couldn't be).
+alter table drops add column word_count integer;
+alter table drops add column reading_minutes integer;
+
+-- Fill in drops with content already. This matches the estimate in
+-- drop.readingMinutes.
+alter table drops disable trigger set_updated_at;
+update drops
+set word_count = counts.words,
+    reading_minutes = ceil(counts.words / 238.0)
+from (
+    select drop_id, (select count(*) from regexp_matches(text, '\S+', 'g')) as words
+    from drop_contents
+    where error is null
+) counts
+where counts.drop_id = drops.id;
+alter table drops enable trigger set_updated_at;
@@ -0,0 +1,27 @@
+select require_migration(1642983811);
+
+alter type drop_status add value 'expired';
+
+create type expiry_action as enum ('expire', 'delete');
+
+-- An expiry policy applies to a user's drops that have had the same status
+-- for too long. The expire action moves them to expired, and the delete
+-- action moves them to the trash.
+create table expiry_policies (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id),
+
+    name text not null,
+    status drop_status not null,
+    max_age_days integer not null check (max_age_days > 0),
+    action expiry_action not null,
+
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp
+);
+create index on expiry_policies (user_id);
+select manage_updated_at('expiry_policies');
+
+-- Changes made by a policy record which one. Like drop_id, this outlives the
+-- policy, so it isn't a foreign key.
+alter table drop_events add column policy_id uuid;
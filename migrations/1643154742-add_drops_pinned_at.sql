@@ -0,0 +1,7 @@
+select require_migration(1643068342);
+
+-- Pinned unread drops come before everything else in Next and List, in the
+-- order they were pinned. Moving or snoozing a drop unpins it, so only unread
+-- drops are ever pinned.
+alter table drops add column pinned_at timestamp;
+create index on drops (user_id, pinned_at) where pinned_at is not null;
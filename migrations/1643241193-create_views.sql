@@ -0,0 +1,16 @@
+select require_migration(1643154742);
+
+-- A view is a saved filter for listing drops. The filter is a drop.ListBody
+-- without a cursor.
+create table views (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id),
+
+    name text not null,
+    filter jsonb not null,
+
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp
+);
+create unique index on views (user_id, name);
+select manage_updated_at('views');
@@ -0,0 +1,5 @@
+select require_migration(1643241193);
+
+-- Tags can be nested. The app keeps the parent links from making a cycle.
+alter table tags add column parent_id uuid references tags(id) on delete set null;
+create index on tags (parent_id);
@@ -0,0 +1,21 @@
+select require_migration(1643327718);
+
+create type tag_rule_match as enum ('domain', 'url', 'title');
+
+-- A tag rule tags a user's drops that match it: by domain (including
+-- subdomains), by a regular expression on the URL, or by keywords that must
+-- all be in the title.
+create table tag_rules (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id),
+
+    match tag_rule_match not null,
+    pattern text not null,
+    tag_id uuid not null references tags(id) on delete cascade,
+
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp
+);
+create index on tag_rules (user_id);
+create index on tag_rules (tag_id);
+select manage_updated_at('tag_rules');
@@ -0,0 +1,4 @@
+select require_migration(1643413521);
+
+alter table tags add column color text;
+alter table tags add column description text;
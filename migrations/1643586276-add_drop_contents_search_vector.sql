@@ -0,0 +1,20 @@
+select require_migration(1643499876);
+
+alter table drop_contents add column search_vector tsvector not null default ''::tsvector;
+
+-- The article text is a weaker signal than the title or URL, so it gets the
+-- lowest weight. Search combines this with drops.search_vector.
+create function drop_contents_search_vector() returns trigger as $$
+begin
+    new.search_vector := setweight(to_tsvector('english', new.text), 'C');
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger set_search_vector before insert or update of text on drop_contents
+    for each row execute procedure drop_contents_search_vector();
+
+-- Backfill the content that has already been fetched.
+update drop_contents set text = text;
+
+create index on drop_contents using gin (search_vector);
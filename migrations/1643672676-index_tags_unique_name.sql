@@ -0,0 +1,14 @@
+select require_migration(1643586276);
+
+-- Tag names are looked up by name now, so they need to be unique per user.
+-- Rename any existing duplicates (keeping the oldest name as-is) so they can
+-- be merged by hand.
+update tags set name = dups.name || ' (' || dups.n || ')'
+from (
+    select id, name, row_number() over (partition by user_id, name order by created_at, id) as n
+    from tags
+) dups
+where tags.id = dups.id and dups.n > 1;
+
+drop index tags_user_id_name_idx;
+create unique index on tags (user_id, name);
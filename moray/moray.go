@@ -91,3 +91,21 @@ func (us *UUIDs) Slice() *[]uuid.UUID {
 	}
 	return (*[]uuid.UUID)(us)
 }
+
+type Strings []string
+
+func (ss *Strings) String() string {
+	if ss == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("[%s]", strings.Join(*ss, ", "))
+}
+
+func (ss *Strings) Type() string {
+	return "[string]"
+}
+
+func (ss *Strings) Set(s string) error {
+	*ss = append(*ss, s)
+	return nil
+}
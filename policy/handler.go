@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+)
+
+type Handler struct{}
+
+type CreateBody struct {
+	Name       string      `json:"name,omitempty"`
+	Status     drop.Status `json:"status,omitempty"`
+	MaxAgeDays int32       `json:"max_age_days,omitempty"`
+	Action     Action      `json:"action,omitempty"`
+}
+
+func (b CreateBody) fields() Fields {
+	return Fields{
+		Name:       b.Name,
+		Status:     b.Status,
+		MaxAgeDays: b.MaxAgeDays,
+		Action:     b.Action,
+	}
+}
+
+func (b CreateBody) Validate() error {
+	return b.fields().validate()
+}
+
+func (Handler) Create(ctx api.Context, u api.User, body CreateBody) (Policy, error) {
+	q := db.New(ctx.Tx)
+	return Create(ctx, q, u, body.fields())
+}
+
+type ListResponse struct {
+	Policies []Policy `json:"policies"`
+}
+
+func (Handler) List(ctx api.Context, u api.User) (ListResponse, error) {
+	q := db.New(ctx.Tx)
+	ps, err := List(ctx, q, u)
+	return ListResponse{Policies: ps}, err
+}
+
+type UpdateBody struct {
+	ID         uuid.UUID    `json:"id,omitempty"`
+	Name       *string      `json:"name,omitempty"`
+	Status     *drop.Status `json:"status,omitempty"`
+	MaxAgeDays *int32       `json:"max_age_days,omitempty"`
+	Action     *Action      `json:"action,omitempty"`
+}
+
+func (Handler) Update(ctx api.Context, u api.User, body UpdateBody) (Policy, error) {
+	q := db.New(ctx.Tx)
+	return Update(ctx, q, u, body.ID, UpdateFields{
+		Name:       body.Name,
+		Status:     body.Status,
+		MaxAgeDays: body.MaxAgeDays,
+		Action:     body.Action,
+	})
+}
+
+type DeleteBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Delete(ctx api.Context, u api.User, body DeleteBody) (Policy, error) {
+	q := db.New(ctx.Tx)
+	return Delete(ctx, q, u, body.ID)
+}
+
+type PreviewBody struct {
+	// ID is the policy to preview. If it's not set, all the user's policies
+	// are previewed.
+	ID *uuid.UUID `json:"id,omitempty"`
+}
+
+type PreviewResponse struct {
+	Previews []Preview `json:"previews"`
+}
+
+func (Handler) Preview(ctx api.Context, u api.User, body PreviewBody) (PreviewResponse, error) {
+	q := db.New(ctx.Tx)
+	ps, err := PreviewAll(ctx, q, u, body.ID, ctx.Clock.Now())
+	return PreviewResponse{Previews: ps}, err
+}
@@ -0,0 +1,295 @@
+// Package policy manages expiry policies, which clean up drops that have sat
+// in the same status for too long. The policies are applied by drop.Expire.
+package policy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+)
+
+// PreviewLimit is the most drops listed for each policy in a preview.
+const PreviewLimit = 50
+
+// An Action is what a policy does to the drops it applies to.
+type Action string
+
+const (
+	// ActionExpire moves drops to the expired status.
+	ActionExpire Action = "expire"
+	// ActionDelete moves drops to the trash.
+	ActionDelete Action = "delete"
+)
+
+// ActionValueStrings returns all valid values of the enum as strings.
+func ActionValueStrings() []string {
+	return []string{
+		string(ActionExpire),
+		string(ActionDelete),
+	}
+}
+
+func (a Action) model() db.ExpiryAction {
+	if a == ActionDelete {
+		return db.ExpiryActionDelete
+	}
+	return db.ExpiryActionExpire
+}
+
+// Implement pflag.Value
+
+func (a *Action) String() string {
+	return string(*a)
+}
+
+func (a *Action) Set(s string) error {
+	switch Action(s) {
+	case ActionExpire, ActionDelete:
+		*a = Action(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown action: %s (expected one of: %s)", s, strings.Join(ActionValueStrings(), ", "))
+	}
+}
+
+func (*Action) Type() string {
+	return "action"
+}
+
+// A Policy acts on a user's drops once they've had the same status for
+// MaxAgeDays days.
+type Policy struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Status     drop.Status `json:"status"`
+	MaxAgeDays int32       `json:"max_age_days"`
+	Action     Action      `json:"action"`
+}
+
+func model(p db.ExpiryPolicy) Policy {
+	return Policy{
+		ID:         p.ID.String(),
+		Name:       p.Name,
+		Status:     drop.StatusModel(p.Status),
+		MaxAgeDays: p.MaxAgeDays,
+		Action:     Action(p.Action),
+	}
+}
+
+type Fields struct {
+	Name       string
+	Status     drop.Status
+	MaxAgeDays int32
+	Action     Action
+}
+
+// validate checks the fields of a whole policy.
+func (f Fields) validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return api.ValidationError("name", f.Name, "must not be blank")
+	}
+	if err := validateStatus(f.Status); err != nil {
+		return err
+	}
+	if err := validateMaxAgeDays(f.MaxAgeDays); err != nil {
+		return err
+	}
+	if err := validateAction(f.Action); err != nil {
+		return err
+	}
+	if f.Status == drop.StatusExpired && f.Action == ActionExpire {
+		return api.ValidationError("action", f.Action, "expired drops can only be deleted")
+	}
+	return nil
+}
+
+func validateStatus(s drop.Status) error {
+	// Snoozed drops already have somewhere to be.
+	switch s {
+	case drop.StatusUnread, drop.StatusRead, drop.StatusSaved, drop.StatusExpired:
+		return nil
+	default:
+		return api.ValidationError("status", s.String(), "must be one of: unread, read, saved, expired")
+	}
+}
+
+func validateMaxAgeDays(days int32) error {
+	if days < 1 {
+		return api.ValidationError("max_age_days", fmt.Sprint(days), "must be positive")
+	}
+	return nil
+}
+
+func validateAction(a Action) error {
+	switch a {
+	case ActionExpire, ActionDelete:
+		return nil
+	default:
+		return api.ValidationError("action", a, fmt.Sprintf("must be one of: %s", strings.Join(ActionValueStrings(), ", ")))
+	}
+}
+
+func Create(ctx context.Context, q db.Queryable, user api.User, f Fields) (Policy, error) {
+	if err := f.validate(); err != nil {
+		return Policy{}, err
+	}
+	p, err := q.ExpiryPolicyCreate(ctx, db.ExpiryPolicyCreateParams{
+		UserID:     user.ID,
+		Name:       f.Name,
+		Status:     f.Status.Model(),
+		MaxAgeDays: f.MaxAgeDays,
+		Action:     f.Action.model(),
+	})
+	if err != nil {
+		return Policy{}, err
+	}
+	return model(p), nil
+}
+
+func List(ctx context.Context, q db.Queryable, user api.User) ([]Policy, error) {
+	ps, err := q.ExpiryPolicyList(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Policy, 0, len(ps))
+	for _, p := range ps {
+		res = append(res, model(p))
+	}
+	return res, nil
+}
+
+func Get(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Policy, error) {
+	p, err := find(ctx, q, user, id)
+	if err != nil {
+		return Policy{}, err
+	}
+	return model(p), nil
+}
+
+func find(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (db.ExpiryPolicy, error) {
+	p, err := q.ExpiryPolicyFind(ctx, db.ExpiryPolicyFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.ExpiryPolicy{}, api.NoResourceError("policy", id.String())
+	}
+	return p, err
+}
+
+type UpdateFields struct {
+	Name       *string
+	Status     *drop.Status
+	MaxAgeDays *int32
+	Action     *Action
+}
+
+// Update changes the fields of a policy that are set.
+func Update(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, f UpdateFields) (Policy, error) {
+	p, err := find(ctx, q, user, id)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	fields := Fields{
+		Name:       p.Name,
+		Status:     drop.StatusModel(p.Status),
+		MaxAgeDays: p.MaxAgeDays,
+		Action:     Action(p.Action),
+	}
+	if f.Name != nil {
+		fields.Name = *f.Name
+	}
+	if f.Status != nil {
+		fields.Status = *f.Status
+	}
+	if f.MaxAgeDays != nil {
+		fields.MaxAgeDays = *f.MaxAgeDays
+	}
+	if f.Action != nil {
+		fields.Action = *f.Action
+	}
+	if err := fields.validate(); err != nil {
+		return Policy{}, err
+	}
+
+	p, err = q.ExpiryPolicyUpdate(ctx, db.ExpiryPolicyUpdateParams{
+		UserID:     user.ID,
+		ID:         id,
+		Name:       fields.Name,
+		Status:     fields.Status.Model(),
+		MaxAgeDays: fields.MaxAgeDays,
+		Action:     fields.Action.model(),
+	})
+	if err != nil {
+		return Policy{}, err
+	}
+	return model(p), nil
+}
+
+func Delete(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Policy, error) {
+	p, err := q.ExpiryPolicyDelete(ctx, db.ExpiryPolicyDeleteParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Policy{}, api.NoResourceError("policy", id.String())
+	}
+	if err != nil {
+		return Policy{}, err
+	}
+	return model(p), nil
+}
+
+// A Preview is what a policy would do if it ran now. Count is the number of
+// drops it would act on, and Drops are the oldest of them, up to
+// PreviewLimit.
+type Preview struct {
+	Policy Policy      `json:"policy"`
+	Count  int64       `json:"count"`
+	Drops  []drop.Drop `json:"drops"`
+}
+
+// PreviewAll previews each of the user's policies, or just one of them if id
+// is set. Each policy is previewed on its own, so a drop that several
+// policies apply to shows up in all of them.
+func PreviewAll(ctx context.Context, q db.Queryable, user api.User, id *uuid.UUID, now time.Time) ([]Preview, error) {
+	var ps []db.ExpiryPolicy
+	if id != nil {
+		p, err := find(ctx, q, user, *id)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	} else {
+		var err error
+		ps, err = q.ExpiryPolicyList(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]Preview, 0, len(ps))
+	for _, p := range ps {
+		ds, n, err := drop.Expiring(ctx, q, p, PreviewLimit, now)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Preview{
+			Policy: model(p),
+			Count:  n,
+			Drops:  ds,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,114 @@
+package policy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/policy"
+)
+
+func TestPolicy(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	_, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Old", URL: "https://example.com/old"}, now.AddDate(0, 0, -90))
+	require.NoError(t, err)
+
+	p, err := policy.Create(ctx, q, user, policy.Fields{
+		Name:       "stale",
+		Status:     drop.StatusUnread,
+		MaxAgeDays: 60,
+		Action:     policy.ActionExpire,
+	})
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(p.ID)
+
+	ps, err := policy.List(ctx, q, user)
+	require.NoError(t, err)
+	assert.Equal(t, []policy.Policy{p}, ps)
+
+	previews, err := policy.PreviewAll(ctx, q, user, &id, now)
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, int64(1), previews[0].Count)
+	require.Len(t, previews[0].Drops, 1)
+	assert.Equal(t, "Old", previews[0].Drops[0].Title)
+
+	// The preview doesn't change anything.
+	d, err := drop.Next(ctx, q, user, now)
+	require.NoError(t, err)
+	assert.Equal(t, drop.StatusUnread, d.Status)
+
+	days := int32(120)
+	p, err = policy.Update(ctx, q, user, id, policy.UpdateFields{MaxAgeDays: &days})
+	require.NoError(t, err)
+	assert.Equal(t, days, p.MaxAgeDays)
+	assert.Equal(t, "stale", p.Name)
+
+	previews, err = policy.PreviewAll(ctx, q, user, nil, now)
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, int64(0), previews[0].Count)
+	assert.Empty(t, previews[0].Drops)
+
+	// Expired drops can't be expired again.
+	expired := drop.StatusExpired
+	_, err = policy.Update(ctx, q, user, id, policy.UpdateFields{Status: &expired})
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+
+	deleted, err := policy.Delete(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Equal(t, p, deleted)
+
+	_, err = policy.Get(ctx, q, user, id)
+	assert.ErrorIs(t, err, api.NoResourceError("policy", id.String()))
+}
+
+func TestCreateBodyValidate(t *testing.T) {
+	valid := policy.CreateBody{
+		Name:       "stale",
+		Status:     drop.StatusUnread,
+		MaxAgeDays: 60,
+		Action:     policy.ActionExpire,
+	}
+	assert.NoError(t, valid.Validate())
+
+	tests := map[string]func(b *policy.CreateBody){
+		"blank name":       func(b *policy.CreateBody) { b.Name = " " },
+		"no status":        func(b *policy.CreateBody) { b.Status = drop.StatusUnknown },
+		"snoozed":          func(b *policy.CreateBody) { b.Status = drop.StatusSnoozed },
+		"zero days":        func(b *policy.CreateBody) { b.MaxAgeDays = 0 },
+		"unknown action":   func(b *policy.CreateBody) { b.Action = "archive" },
+		"expiring expired": func(b *policy.CreateBody) { b.Status = drop.StatusExpired },
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := valid
+			tt(&b)
+			var aerr api.Error
+			require.ErrorAs(t, b.Validate(), &aerr)
+			assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+		})
+	}
+
+	b := valid
+	b.Status = drop.StatusExpired
+	b.Action = policy.ActionDelete
+	assert.NoError(t, b.Validate())
+}
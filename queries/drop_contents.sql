@@ -0,0 +1,29 @@
+-- name: DropContentFind :one
+select * from drop_contents
+where user_id = $1 and drop_id = $2;
+
+-- name: DropContentPending :one
+select drops.* from drops
+left join drop_contents on drop_contents.drop_id = drops.id
+where drop_contents.drop_id is null and drops.deleted_at is null
+order by drops.created_at asc
+limit 1
+for update of drops skip locked;
+
+-- name: DropContentSave :one
+insert into drop_contents
+(drop_id, user_id, url, title, text, html, error, fetched_at)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+on conflict (drop_id) do update
+set url = excluded.url,
+    title = excluded.title,
+    text = excluded.text,
+    html = excluded.html,
+    error = excluded.error,
+    fetched_at = excluded.fetched_at
+returning *;
+
+-- name: DropContentsDelete :many
+delete from drop_contents
+where user_id = $1 and drop_id = $2
+returning *;
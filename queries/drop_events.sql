@@ -0,0 +1,10 @@
+-- name: DropEventCreate :one
+insert into drop_events
+(user_id, drop_id, kind, before, after, policy_id)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: DropEventList :many
+select * from drop_events
+where user_id = $1 and drop_id = $2
+order by created_at asc, id asc;
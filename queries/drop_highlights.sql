@@ -0,0 +1,26 @@
+-- name: DropHighlightFind :one
+select * from drop_highlights where user_id = $1 and id = $2;
+
+-- name: DropHighlightsList :many
+select * from drop_highlights
+where user_id = $1 and drop_id = ANY(@drop_ids::uuid[])
+order by created_at asc, id asc;
+
+-- name: DropHighlightCreate :one
+insert into drop_highlights (user_id, drop_id, quote, comment)
+values ($1, $2, $3, $4)
+returning *;
+
+-- name: DropHighlightImport :one
+-- Imported highlights keep their own created_at times.
+insert into drop_highlights (user_id, drop_id, quote, comment, created_at, updated_at)
+values ($1, $2, $3, $4, @created_at, @created_at)
+returning *;
+
+-- name: DropHighlightUpdate :one
+update drop_highlights set quote = $3, comment = $4
+where user_id = $1 and id = $2
+returning *;
+
+-- name: DropHighlightDelete :one
+delete from drop_highlights where user_id = $1 and id = $2 returning *;
@@ -6,7 +6,39 @@ returning *;
 
 -- name: DropTagsIntersect :many
 delete from drop_tags
-where drop_id = $1 and tag_id != any(@tag_ids::uuid[])
+where drop_id = $1 and not (tag_id = any(@tag_ids::uuid[]))
+returning *;
+
+-- name: DropTagsRemove :many
+delete from drop_tags
+where drop_id = $1 and tag_id = any(@tag_ids::uuid[])
+returning *;
+
+-- name: DropTagsDetach :many
+delete from drop_tags
+using tags
+where drop_tags.tag_id = tags.id and tags.user_id = $1 and tags.id = $2
+returning drop_tags.*;
+
+-- name: DropTagsMergeDuplicates :many
+-- Before merging, remove the source rows that would become duplicates: those
+-- on drops that already have the target, and all but one of the sources on
+-- each drop.
+delete from drop_tags
+where drop_tags.tag_id = any(@source_ids::uuid[])
+and exists (
+    select 1 from drop_tags other
+    where other.drop_id = drop_tags.drop_id
+    and (
+        other.tag_id = @target_id
+        or (other.tag_id = any(@source_ids::uuid[]) and other.id < drop_tags.id)
+    )
+)
+returning *;
+
+-- name: DropTagsRepoint :many
+update drop_tags set tag_id = @target_id
+where tag_id = any(@source_ids::uuid[])
 returning *;
 
 -- custom: DropTagsApply
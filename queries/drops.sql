@@ -1,31 +1,166 @@
 -- name: DropFind :one
 select * from drops
-where user_id = $1 and id = $2;
+where user_id = $1 and id = $2 and deleted_at is null;
+
+-- name: DropFindByCanonicalURL :one
+select * from drops
+where user_id = $1 and canonical_url = $2 and deleted_at is null;
 
 -- name: DropNext :one
 select * from drops
-where user_id = $1 and status = 'unread'
-order by moved_at asc;
+where user_id = $1 and deleted_at is null
+and (status = 'unread' or (status = 'snoozed' and snooze_until <= @now::timestamp))
+order by pinned_at asc nulls last, moved_at asc;
 
 -- name: DropList :many
 select * from drops
-where user_id = $1 and status = ANY(@statuses::drop_status[])
-order by moved_at asc
+where user_id = $1 and deleted_at is null and status = ANY(@statuses::drop_status[])
+and (status != 'snoozed' or (snooze_until <= @now::timestamp) = @awake::bool)
+and (pinned_at is null, coalesce(pinned_at, '0001-01-01'), moved_at, id)
+    > (@after_unpinned::bool, @after_pinned_at::timestamp, @after_moved_at::timestamp, @after_id::uuid)
+order by pinned_at asc nulls last, moved_at asc, id asc
 limit $2;
 
+-- name: DropSearch :many
+-- Matches on the fetched article text too, if there is any.
+select drops.*,
+    ts_rank(drops.search_vector || coalesce(drop_contents.search_vector, ''::tsvector), query)::real as rank,
+    ts_headline('english', coalesce(drops.title, '') || ' ' || drops.url || ' ' || coalesce(drop_contents.text, ''), query)::text as snippet
+from drops
+cross join websearch_to_tsquery('english', @query::text) query
+left join drop_contents on drop_contents.drop_id = drops.id
+where drops.user_id = @user_id and drops.deleted_at is null
+and (drops.search_vector @@ query or drop_contents.search_vector @@ query)
+order by rank desc, drops.moved_at asc
+limit @max_results;
+
 -- name: DropCreate :one
 insert into drops
-(user_id, title, url, status, moved_at)
-values ($1, $2, $3, $4, $5)
+(user_id, title, url, canonical_url, status, moved_at)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: DropImport :one
+-- Unlike DropCreate, imported drops keep their own created_at and pinned_at,
+-- and a snooze_until separate from moved_at.
+insert into drops
+(user_id, title, url, canonical_url, status, moved_at, created_at, snooze_until, pinned_at)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 returning *;
 
 -- name: DropMove :one
 update drops
-set status = $3, moved_at = $4
-where user_id = $1 and id = $2
+set status = $3, moved_at = $4, snooze_until = null, pinned_at = null
+where user_id = $1 and id = $2 and deleted_at is null
 returning *;
 
--- name: DropDelete :one
-delete from drops where user_id = $1 and id = $2 returning *;
+-- name: DropSnooze :one
+update drops
+set status = 'snoozed', moved_at = @snooze_until::timestamp, snooze_until = @snooze_until::timestamp, pinned_at = null
+where user_id = $1 and id = $2 and deleted_at is null
+returning *;
+
+-- name: DropPin :one
+update drops
+set status = 'unread', snooze_until = null, pinned_at = coalesce(pinned_at, @pinned_at::timestamp)
+where user_id = $1 and id = $2 and deleted_at is null
+returning *;
+
+-- name: DropUnpin :one
+update drops set pinned_at = null
+where user_id = $1 and id = $2 and deleted_at is null
+returning *;
+
+-- name: DropsWake :many
+update drops
+set status = 'unread', snooze_until = null
+where status = 'snoozed' and snooze_until <= @now::timestamp and deleted_at is null
+returning *;
+
+-- name: DropTrash :one
+update drops set deleted_at = @deleted_at::timestamp
+where user_id = $1 and id = $2 and deleted_at is null
+returning *;
+
+-- name: DropFindTrashed :one
+select * from drops
+where user_id = $1 and id = $2 and deleted_at is not null;
+
+-- name: DropsTrashed :many
+select * from drops
+where user_id = $1 and deleted_at is not null
+order by deleted_at desc, id desc;
+
+-- name: DropRestore :one
+update drops set deleted_at = null
+where user_id = $1 and id = $2 and deleted_at is not null
+returning *;
+
+-- name: DropsEmptyTrash :many
+delete from drops
+where user_id = $1 and deleted_at is not null
+returning id;
+
+-- name: DropsPurge :many
+delete from drops
+where id in (
+    select id from drops
+    where deleted_at <= @trashed_before::timestamp
+    order by deleted_at asc
+    limit @max_drops
+    for update skip locked
+)
+returning id;
+
+-- name: DropsExpirable :many
+select * from drops
+where user_id = $1 and status = $2 and moved_at <= @moved_before::timestamp and deleted_at is null
+order by moved_at asc, id asc
+limit @max_drops
+for update skip locked;
+
+-- name: DropsExpiring :many
+select * from drops
+where user_id = $1 and status = $2 and moved_at <= @moved_before::timestamp and deleted_at is null
+order by moved_at asc, id asc
+limit @max_drops;
+
+-- name: DropsExpiringCount :one
+select count(*) from drops
+where user_id = $1 and status = $2 and moved_at <= @moved_before::timestamp and deleted_at is null;
+
+-- name: DropMetadataPending :one
+select * from drops
+where metadata_fetched_at is null and coalesce(title, '') = '' and deleted_at is null
+order by created_at asc
+limit 1
+for update skip locked;
+
+-- name: DropSetMetadata :one
+update drops
+set title = coalesce(nullif(title, ''), nullif(@title::text, '')),
+    description = @description,
+    site_name = @site_name,
+    image_url = @image_url,
+    metadata_fetched_at = @fetched_at::timestamp
+where id = @id
+returning *;
+
+-- name: DropSetReadingTime :one
+update drops
+set word_count = @word_count,
+    reading_minutes = @reading_minutes
+where id = @id
+returning *;
+
+-- name: DropsTagged :many
+-- Includes trashed drops, since tag changes apply to them too.
+select * from drops
+where user_id = @user_id and exists (
+    select 1 from drop_tags
+    where drop_tags.drop_id = drops.id and drop_tags.tag_id = any(@tag_ids::uuid[])
+)
+order by id;
 
 -- custom: DropUpdate
+-- custom: DropsExport
@@ -0,0 +1,25 @@
+-- name: ExpiryPolicyFind :one
+select * from expiry_policies where user_id = $1 and id = $2;
+
+-- name: ExpiryPolicyList :many
+select * from expiry_policies
+where user_id = $1
+order by created_at asc, id asc;
+
+-- name: ExpiryPoliciesAll :many
+select * from expiry_policies order by max_age_days desc, id asc;
+
+-- name: ExpiryPolicyCreate :one
+insert into expiry_policies
+(user_id, name, status, max_age_days, action)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: ExpiryPolicyUpdate :one
+update expiry_policies
+set name = $3, status = $4, max_age_days = $5, action = $6
+where user_id = $1 and id = $2
+returning *;
+
+-- name: ExpiryPolicyDelete :one
+delete from expiry_policies where user_id = $1 and id = $2 returning *;
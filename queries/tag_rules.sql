@@ -0,0 +1,27 @@
+-- name: TagRuleFind :one
+select * from tag_rules where user_id = $1 and id = $2;
+
+-- name: TagRuleList :many
+select * from tag_rules
+where user_id = $1
+order by created_at asc, id asc;
+
+-- name: TagRuleCreate :one
+insert into tag_rules
+(user_id, match, pattern, tag_id)
+values ($1, $2, $3, $4)
+returning *;
+
+-- name: TagRuleUpdate :one
+update tag_rules
+set match = $3, pattern = $4, tag_id = $5
+where user_id = $1 and id = $2
+returning *;
+
+-- name: TagRuleDelete :one
+delete from tag_rules where user_id = $1 and id = $2 returning *;
+
+-- name: TagRulesRetag :many
+update tag_rules set tag_id = @target_id
+where user_id = $1 and tag_id = any(@source_ids::uuid[])
+returning *;
@@ -4,6 +4,9 @@ select * from tags where user_id = $1 and id = $2;
 -- name: TagFindAll :many
 select * from tags where user_id = $1 and id = ANY(@ids::uuid[]);
 
+-- name: TagFindByNames :many
+select * from tags where user_id = $1 and name = ANY(@names::text[]);
+
 -- name: TagList :many
 select * from tags where user_id = $1;
 
@@ -22,8 +25,29 @@ where drops.user_id = $1 and drops.id = ANY(@drop_ids::uuid[]);
 -- name: TagCreate :one
 insert into tags (user_id, name) values ($1, $2) returning *;
 
+-- name: TagsCreateMissing :many
+-- Creates the tags that don't exist yet. Use TagFindByNames afterwards to get
+-- all of them, including ones another transaction just created.
+insert into tags (user_id, name)
+select @user_id, unnest(@names::text[])
+on conflict (user_id, name) do nothing
+returning *;
+
 -- name: TagMove :one
 update tags set name = $3 where user_id = $1 and id = $2 returning *;
 
 -- name: TagDelete :one
 delete from tags where user_id = $1 and id = $2 returning *;
+
+-- name: TagSetParent :one
+update tags set parent_id = @parent_id where user_id = $1 and id = $2 returning *;
+
+-- name: TagsReparent :many
+update tags set parent_id = @parent_id
+where user_id = $1 and parent_id = any(@old_parent_ids::uuid[])
+returning *;
+
+-- name: TagUpdate :one
+update tags set color = @color, description = @description
+where user_id = $1 and id = $2
+returning *;
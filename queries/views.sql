@@ -0,0 +1,19 @@
+-- name: ViewFind :one
+select * from views where user_id = $1 and id = $2;
+
+-- name: ViewFindByName :one
+select * from views where user_id = $1 and name = $2;
+
+-- name: ViewList :many
+select * from views where user_id = $1 order by name asc, id asc;
+
+-- name: ViewCreate :one
+insert into views (user_id, name, filter) values ($1, $2, $3) returning *;
+
+-- name: ViewUpdate :one
+update views set name = $3, filter = $4
+where user_id = $1 and id = $2
+returning *;
+
+-- name: ViewDelete :one
+delete from views where user_id = $1 and id = $2 returning *;
@@ -0,0 +1,328 @@
+// Package readable extracts the main content of web pages, leaving out the
+// navigation, ads, and other clutter around it.
+//
+// The extractor follows the same idea as Arc90's Readability: score each block
+// by how much prose it contains, credit the score to its ancestors, and pick
+// the ancestor that collected the most.
+package readable
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var ErrNoContent = errors.New("no readable content found")
+
+// An Article is the main content of a page.
+type Article struct {
+	Title string
+	// Text is the content as plain text, with blocks separated by blank
+	// lines. Preformatted blocks are indented by four spaces.
+	Text string
+	// HTML is the content with everything but basic formatting removed.
+	HTML string
+}
+
+// Extract finds the main content of an HTML document. Relative links and
+// images are resolved against base.
+func Extract(r io.Reader, base *url.URL) (Article, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Article{}, err
+	}
+
+	title := ""
+	if t := find(doc, "title"); t != nil {
+		title = collapse(innerText(t))
+	}
+
+	body := find(doc, "body")
+	if body == nil {
+		return Article{}, ErrNoContent
+	}
+	prune(body)
+
+	nodes := content(body)
+	if len(nodes) == 0 {
+		return Article{}, ErrNoContent
+	}
+
+	var (
+		h strings.Builder
+		t textWriter
+	)
+	for _, n := range nodes {
+		writeHTML(&h, n, base)
+		t.walk(n, "")
+	}
+	t.flush("")
+
+	text := strings.Join(t.blocks, "\n\n")
+	if text == "" {
+		return Article{}, ErrNoContent
+	}
+	return Article{
+		Title: title,
+		Text:  text,
+		HTML:  h.String(),
+	}, nil
+}
+
+var (
+	// unlikely matches the class and ID names of page furniture.
+	unlikely = regexp.MustCompile(`(?i)ad-break|agegate|banner|breadcrumb|combx|comment|community|cookie|disqus|extra|footer|header|menu|modal|nav|pager|pagination|popup|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe`)
+	// maybe rescues elements that unlikely would remove, like "main-nav-content".
+	maybe = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+
+	positive = regexp.MustCompile(`(?i)article|body|content|entry|h-entry|hentry|main|page|post|text|blog|story`)
+	negative = regexp.MustCompile(`(?i)banner|com-|combx|comment|contact|foot|footnote|hidden|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// junk elements are never part of the content.
+var junk = set("aside", "button", "canvas", "embed", "footer", "form", "header", "iframe", "input", "link", "nav", "noscript", "object", "script", "select", "style", "svg", "template", "textarea")
+
+// prune removes comments, junk elements, and anything that looks like page
+// furniture.
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode,
+			c.Type == html.ElementNode && (junk[c.Data] || isUnlikely(c)):
+			n.RemoveChild(c)
+		case c.Type == html.ElementNode:
+			prune(c)
+		}
+		c = next
+	}
+}
+
+func isUnlikely(n *html.Node) bool {
+	switch n.Data {
+	case "a", "article", "body", "main":
+		return false
+	}
+	names := attr(n, "class") + " " + attr(n, "id")
+	return unlikely.MatchString(names) && !maybe.MatchString(names)
+}
+
+// content returns the nodes that make up the main content, in document order.
+func content(body *html.Node) []*html.Node {
+	scores := score(body)
+
+	var (
+		top      *html.Node
+		topScore float64
+	)
+	// Go in document order so ties always go to the same node.
+	walk(body, func(n *html.Node) {
+		s, ok := scores[n]
+		if !ok {
+			return
+		}
+		s *= 1 - linkDensity(n)
+		scores[n] = s
+		if top == nil || s > topScore {
+			top, topScore = n, s
+		}
+	})
+	if top == nil {
+		return nil
+	}
+
+	// Articles are often split across several siblings, like a lead image
+	// and the text, so include any neighbors that look related.
+	threshold := topScore * 0.2
+	if threshold < 10 {
+		threshold = 10
+	}
+	if top.Parent == nil || top == body {
+		return []*html.Node{top}
+	}
+	var nodes []*html.Node
+	for s := top.Parent.FirstChild; s != nil; s = s.NextSibling {
+		if s.Type != html.ElementNode {
+			continue
+		}
+		if s == top || related(s, scores, threshold) {
+			nodes = append(nodes, s)
+		}
+	}
+	return nodes
+}
+
+func related(n *html.Node, scores map[*html.Node]float64, threshold float64) bool {
+	if s, ok := scores[n]; ok && s >= threshold {
+		return true
+	}
+	if n.Data != "p" {
+		return false
+	}
+	text := collapse(innerText(n))
+	density := linkDensity(n)
+	if len(text) > 80 {
+		return density < 0.25
+	}
+	return density == 0 && strings.Contains(text, ". ")
+}
+
+// score rates the ancestors of every paragraph-like block by the amount of
+// prose in the block.
+func score(body *html.Node) map[*html.Node]float64 {
+	scores := make(map[*html.Node]float64)
+	walk(body, func(n *html.Node) {
+		if !isParagraph(n) {
+			return
+		}
+		text := collapse(innerText(n))
+		if len(text) < 25 {
+			return
+		}
+
+		// One point for being a paragraph, one for each comma, and one for
+		// every hundred characters up to three.
+		points := 1 + float64(strings.Count(text, ","))
+		if l := float64(len(text) / 100); l < 3 {
+			points += l
+		} else {
+			points += 3
+		}
+
+		// The parent gets full credit, and further ancestors get less.
+		divisors := []float64{1, 2, 6}
+		a := n.Parent
+		for _, d := range divisors {
+			if a == nil || a.Type != html.ElementNode {
+				break
+			}
+			if _, ok := scores[a]; !ok {
+				scores[a] = initialScore(a)
+			}
+			scores[a] += points / d
+			a = a.Parent
+		}
+	})
+	return scores
+}
+
+var blocks = set("article", "blockquote", "div", "dl", "figure", "h1", "h2", "h3", "h4", "h5", "h6", "img", "ol", "p", "pre", "section", "table", "ul")
+
+// isParagraph reports whether n holds prose. Plenty of pages use <div>s for
+// paragraphs, so those count if they don't contain other blocks.
+func isParagraph(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.Data {
+	case "p", "pre", "td", "blockquote":
+		return true
+	case "div":
+		hasBlock := false
+		walk(n, func(c *html.Node) {
+			if c != n && c.Type == html.ElementNode && blocks[c.Data] {
+				hasBlock = true
+			}
+		})
+		return !hasBlock
+	default:
+		return false
+	}
+}
+
+func initialScore(n *html.Node) float64 {
+	var s float64
+	switch n.Data {
+	case "div":
+		s = 5
+	case "pre", "td", "blockquote":
+		s = 3
+	case "address", "dd", "dl", "dt", "li", "ol", "ul", "form":
+		s = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		s = -5
+	}
+	for _, name := range []string{attr(n, "class"), attr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if positive.MatchString(name) {
+			s += 25
+		}
+		if negative.MatchString(name) {
+			s -= 25
+		}
+	}
+	return s
+}
+
+// linkDensity is the fraction of the text in n that's inside links. Lists of
+// links are navigation, not content.
+func linkDensity(n *html.Node) float64 {
+	total := len(collapse(innerText(n)))
+	if total == 0 {
+		return 0
+	}
+	var links int
+	walk(n, func(c *html.Node) {
+		if c.Type == html.ElementNode && c.Data == "a" {
+			links += len(collapse(innerText(c)))
+		}
+	})
+	return float64(links) / float64(total)
+}
+
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func find(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if f := find(c, tag); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func innerText(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	})
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// collapse replaces runs of whitespace with single spaces.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func set(vals ...string) map[string]bool {
+	m := make(map[string]bool, len(vals))
+	for _, v := range vals {
+		m[v] = true
+	}
+	return m
+}
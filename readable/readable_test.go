@@ -0,0 +1,69 @@
+package readable_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/readable"
+)
+
+const page = `<!doctype html>
+<html>
+<head><title>A Fine Article</title><script>track()</script></head>
+<body>
+<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+<div class="sidebar"><p>Subscribe to our newsletter, it is great, really, truly great.</p></div>
+<div id="main">
+  <article class="post-body">
+    <h1>A Fine Article</h1>
+    <p>This is the first paragraph of the article, and it has plenty of words in it, which is good.</p>
+    <p>Here is a second paragraph with a <a href="/more">relative link</a>, some <em>emphasis</em>, and a <a href="javascript:void(0)">script link</a>.</p>
+    <img src="/lead.png" alt="Lead">
+    <ul><li>First point</li><li>Second point</li></ul>
+    <pre>func main() {
+	fmt.Println("hi")
+}</pre>
+    <blockquote><p>A quotation that somebody once said, probably.</p></blockquote>
+  </article>
+  <div class="comments"><p>First! This comment is long enough to be scored, sadly, very sadly.</p></div>
+</div>
+<footer><p>Copyright 2022, all rights reserved, and so on and so forth.</p></footer>
+</body>
+</html>`
+
+func TestExtract(t *testing.T) {
+	base, err := url.Parse("https://example.com/posts/fine")
+	require.NoError(t, err)
+
+	a, err := readable.Extract(strings.NewReader(page), base)
+	require.NoError(t, err)
+
+	assert.Equal(t, "A Fine Article", a.Title)
+	assert.Equal(t, strings.Join([]string{
+		"A Fine Article",
+		"This is the first paragraph of the article, and it has plenty of words in it, which is good.",
+		"Here is a second paragraph with a relative link, some emphasis, and a script link.",
+		"- First point",
+		"- Second point",
+		"    func main() {\n    \tfmt.Println(\"hi\")\n    }",
+		"> A quotation that somebody once said, probably.",
+	}, "\n\n"), a.Text)
+
+	assert.Contains(t, a.HTML, `<a href="https://example.com/more">relative link</a>`)
+	assert.Contains(t, a.HTML, `and a script link.`)
+	assert.Contains(t, a.HTML, `<img src="https://example.com/lead.png" alt="Lead">`)
+	assert.Contains(t, a.HTML, `<em>emphasis</em>`)
+	assert.NotContains(t, a.HTML, "newsletter")
+	assert.NotContains(t, a.HTML, "First!")
+	assert.NotContains(t, a.HTML, "Copyright")
+	assert.NotContains(t, a.HTML, "class=")
+}
+
+func TestExtractNoContent(t *testing.T) {
+	_, err := readable.Extract(strings.NewReader(`<html><body><nav><a href="/">Home</a></nav></body></html>`), nil)
+	assert.ErrorIs(t, err, readable.ErrNoContent)
+}
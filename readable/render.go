@@ -0,0 +1,171 @@
+package readable
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowed are the elements kept in the cleaned HTML. Anything else is
+// replaced by its children.
+var allowed = set("a", "b", "blockquote", "br", "code", "dd", "dl", "dt", "em", "figcaption", "figure", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "li", "ol", "p", "pre", "strong", "sub", "sup", "table", "tbody", "td", "th", "thead", "tr", "ul")
+
+var void = set("br", "hr", "img")
+
+// writeHTML renders n with only the allowed elements, and only the attributes
+// needed for links and images.
+func writeHTML(b *strings.Builder, n *html.Node, base *url.URL) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	var attrs []html.Attribute
+	switch n.Data {
+	case "a":
+		if href := resolve(base, attr(n, "href")); href != "" {
+			attrs = append(attrs, html.Attribute{Key: "href", Val: href})
+		}
+	case "img":
+		// Lazy-loading scripts often leave the real source in data-src.
+		src := attr(n, "src")
+		if src == "" {
+			src = attr(n, "data-src")
+		}
+		src = resolve(base, src)
+		if src == "" {
+			return
+		}
+		attrs = append(attrs, html.Attribute{Key: "src", Val: src})
+		if alt := attr(n, "alt"); alt != "" {
+			attrs = append(attrs, html.Attribute{Key: "alt", Val: alt})
+		}
+	}
+
+	// Links without a usable target are just text.
+	keep := allowed[n.Data] && (n.Data != "a" || len(attrs) > 0)
+	if keep {
+		b.WriteString("<" + n.Data)
+		for _, a := range attrs {
+			b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+		}
+		b.WriteString(">")
+		if void[n.Data] {
+			return
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeHTML(b, c, base)
+	}
+	if keep {
+		b.WriteString("</" + n.Data + ">")
+	}
+}
+
+// resolve makes ref absolute relative to base. Only http(s) URLs are kept, so
+// javascript: links and data: images are dropped.
+func resolve(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// breaks are the elements that start a new block of text.
+var breaks = set("address", "article", "blockquote", "dd", "div", "dl", "dt", "figcaption", "figure", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "li", "main", "ol", "p", "section", "table", "tr", "ul")
+
+// A textWriter collects the text of a document as a list of blocks.
+type textWriter struct {
+	blocks []string
+	line   strings.Builder
+}
+
+func (w *textWriter) walk(n *html.Node, prefix string) {
+	switch n.Type {
+	case html.TextNode:
+		w.line.WriteString(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.Data {
+	case "pre":
+		w.flush(prefix)
+		// Keep the formatting, but indent it so it stands out from the
+		// prose around it.
+		text := strings.Trim(innerText(n), "\n")
+		if strings.TrimSpace(text) != "" {
+			lines := strings.Split(text, "\n")
+			for i, l := range lines {
+				lines[i] = prefix + "    " + strings.TrimRight(l, " \t")
+			}
+			w.blocks = append(w.blocks, strings.Join(lines, "\n"))
+		}
+		return
+	case "br":
+		w.line.WriteString(" ")
+		return
+	case "blockquote":
+		w.flush(prefix)
+		w.children(n, prefix+"> ")
+		w.flush(prefix + "> ")
+		return
+	case "ol", "ul":
+		w.flush(prefix)
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data != "li" {
+				w.walk(c, prefix)
+				continue
+			}
+			i++
+			bullet := "- "
+			if n.Data == "ol" {
+				bullet = strconv.Itoa(i) + ". "
+			}
+			w.children(c, prefix+bullet)
+			w.flush(prefix + bullet)
+		}
+		return
+	}
+
+	if breaks[n.Data] {
+		w.flush(prefix)
+		w.children(n, prefix)
+		w.flush(prefix)
+		return
+	}
+	w.children(n, prefix)
+}
+
+func (w *textWriter) children(n *html.Node, prefix string) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c, prefix)
+	}
+}
+
+// flush ends the current block, if it has any text.
+func (w *textWriter) flush(prefix string) {
+	if s := collapse(w.line.String()); s != "" {
+		w.blocks = append(w.blocks, prefix+s)
+	}
+	w.line.Reset()
+}
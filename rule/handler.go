@@ -0,0 +1,86 @@
+package rule
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+type Handler struct{}
+
+type CreateBody struct {
+	Match   Match     `json:"match,omitempty"`
+	Pattern string    `json:"pattern,omitempty"`
+	TagID   uuid.UUID `json:"tag_id,omitempty"`
+}
+
+func (b CreateBody) fields() Fields {
+	return Fields{
+		Match:   b.Match,
+		Pattern: b.Pattern,
+		TagID:   b.TagID,
+	}
+}
+
+func (b CreateBody) Validate() error {
+	return b.fields().validate()
+}
+
+func (Handler) Create(ctx api.Context, u api.User, body CreateBody) (Rule, error) {
+	q := db.New(ctx.Tx)
+	return Create(ctx, q, u, body.fields())
+}
+
+type ListResponse struct {
+	Rules []Rule `json:"rules"`
+}
+
+func (Handler) List(ctx api.Context, u api.User) (ListResponse, error) {
+	q := db.New(ctx.Tx)
+	rs, err := List(ctx, q, u)
+	return ListResponse{Rules: rs}, err
+}
+
+type UpdateBody struct {
+	ID      uuid.UUID  `json:"id,omitempty"`
+	Match   *Match     `json:"match,omitempty"`
+	Pattern *string    `json:"pattern,omitempty"`
+	TagID   *uuid.UUID `json:"tag_id,omitempty"`
+}
+
+func (Handler) Update(ctx api.Context, u api.User, body UpdateBody) (Rule, error) {
+	q := db.New(ctx.Tx)
+	return Update(ctx, q, u, body.ID, UpdateFields{
+		Match:   body.Match,
+		Pattern: body.Pattern,
+		TagID:   body.TagID,
+	})
+}
+
+type DeleteBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Delete(ctx api.Context, u api.User, body DeleteBody) (Rule, error) {
+	q := db.New(ctx.Tx)
+	return Delete(ctx, q, u, body.ID)
+}
+
+type ApplyBody struct {
+	// ID is the rule to apply. If it's not set, all the user's rules are
+	// applied.
+	ID *uuid.UUID `json:"id,omitempty"`
+	// DryRun shows which drops the rules would tag without tagging them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+type ApplyResponse struct {
+	Results []Result `json:"results"`
+}
+
+func (Handler) Apply(ctx api.Context, u api.User, body ApplyBody) (ApplyResponse, error) {
+	q := db.New(ctx.Tx)
+	rs, err := Apply(ctx, q, u, body.ID, body.DryRun)
+	return ApplyResponse{Results: rs}, err
+}
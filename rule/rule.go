@@ -0,0 +1,321 @@
+// Package rule manages tag rules, which tag drops automatically. New drops
+// are tagged by drop.Create, and existing ones when the rules are applied.
+package rule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+)
+
+// PreviewLimit is the most drops listed for each rule when applying rules.
+const PreviewLimit = 50
+
+// A Match is the part of a drop that a rule checks.
+type Match string
+
+const (
+	// MatchDomain matches drops from the domain or any of its subdomains.
+	MatchDomain Match = "domain"
+	// MatchURL matches drops whose URL matches a regular expression.
+	MatchURL Match = "url"
+	// MatchTitle matches drops whose title has all of the keywords, in any
+	// case.
+	MatchTitle Match = "title"
+)
+
+// MatchValueStrings returns all valid values of the enum as strings.
+func MatchValueStrings() []string {
+	return []string{
+		string(MatchDomain),
+		string(MatchURL),
+		string(MatchTitle),
+	}
+}
+
+func (m Match) model() db.TagRuleMatch {
+	switch m {
+	case MatchURL:
+		return db.TagRuleMatchUrl
+	case MatchTitle:
+		return db.TagRuleMatchTitle
+	default:
+		return db.TagRuleMatchDomain
+	}
+}
+
+// Implement pflag.Value
+
+func (m *Match) String() string {
+	return string(*m)
+}
+
+func (m *Match) Set(s string) error {
+	switch Match(s) {
+	case MatchDomain, MatchURL, MatchTitle:
+		*m = Match(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown match: %s (expected one of: %s)", s, strings.Join(MatchValueStrings(), ", "))
+	}
+}
+
+func (*Match) Type() string {
+	return "match"
+}
+
+// A Rule tags a user's drops that match the pattern.
+type Rule struct {
+	ID      string `json:"id"`
+	Match   Match  `json:"match"`
+	Pattern string `json:"pattern"`
+	TagID   string `json:"tag_id"`
+}
+
+func model(r db.TagRule) Rule {
+	return Rule{
+		ID:      r.ID.String(),
+		Match:   Match(r.Match),
+		Pattern: r.Pattern,
+		TagID:   r.TagID.String(),
+	}
+}
+
+type Fields struct {
+	Match   Match
+	Pattern string
+	TagID   uuid.UUID
+}
+
+// validate checks the fields of a whole rule.
+func (f Fields) validate() error {
+	switch f.Match {
+	case MatchDomain, MatchURL, MatchTitle:
+	default:
+		return api.ValidationError("match", f.Match, fmt.Sprintf("must be one of: %s", strings.Join(MatchValueStrings(), ", ")))
+	}
+	if strings.TrimSpace(f.Pattern) == "" {
+		return api.ValidationError("pattern", f.Pattern, "must not be blank")
+	}
+	return nil
+}
+
+// checkPattern returns a validation error if a URL rule's pattern isn't a
+// regular expression that Postgres accepts. Postgres runs the pattern, and its
+// syntax isn't quite the same as Go's, so it has to be the one to check.
+//
+// A failed query aborts the transaction, so this runs in a savepoint.
+func checkPattern(ctx context.Context, q db.Queryable, f Fields) error {
+	if f.Match != MatchURL {
+		return nil
+	}
+	if _, err := q.ExecContext(ctx, "SAVEPOINT check_pattern"); err != nil {
+		return err
+	}
+
+	var ok bool
+	err := q.QueryRowContext(ctx, "SELECT '' ~ $1", f.Pattern).Scan(&ok)
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && pgerr.Code == "2201B" { // invalid_regular_expression
+		if _, err := q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT check_pattern"); err != nil {
+			return err
+		}
+		return api.ValidationError("pattern", f.Pattern, "must be a valid regular expression")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, "RELEASE SAVEPOINT check_pattern")
+	return err
+}
+
+// checkTag returns an error if the user doesn't have the tag.
+func checkTag(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) error {
+	_, err := q.TagFind(ctx, db.TagFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.NoResourceError("tag", id.String())
+	}
+	return err
+}
+
+func Create(ctx context.Context, q db.Queryable, user api.User, f Fields) (Rule, error) {
+	if err := f.validate(); err != nil {
+		return Rule{}, err
+	}
+	if err := checkPattern(ctx, q, f); err != nil {
+		return Rule{}, err
+	}
+	if err := checkTag(ctx, q, user, f.TagID); err != nil {
+		return Rule{}, err
+	}
+	r, err := q.TagRuleCreate(ctx, db.TagRuleCreateParams{
+		UserID:  user.ID,
+		Match:   f.Match.model(),
+		Pattern: f.Pattern,
+		TagID:   f.TagID,
+	})
+	if err != nil {
+		return Rule{}, err
+	}
+	return model(r), nil
+}
+
+func List(ctx context.Context, q db.Queryable, user api.User) ([]Rule, error) {
+	rs, err := q.TagRuleList(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Rule, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, model(r))
+	}
+	return res, nil
+}
+
+func Get(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Rule, error) {
+	r, err := find(ctx, q, user, id)
+	if err != nil {
+		return Rule{}, err
+	}
+	return model(r), nil
+}
+
+func find(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (db.TagRule, error) {
+	r, err := q.TagRuleFind(ctx, db.TagRuleFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.TagRule{}, api.NoResourceError("rule", id.String())
+	}
+	return r, err
+}
+
+type UpdateFields struct {
+	Match   *Match
+	Pattern *string
+	TagID   *uuid.UUID
+}
+
+// Update changes the fields of a rule that are set.
+func Update(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, f UpdateFields) (Rule, error) {
+	r, err := find(ctx, q, user, id)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	fields := Fields{
+		Match:   Match(r.Match),
+		Pattern: r.Pattern,
+		TagID:   r.TagID,
+	}
+	if f.Match != nil {
+		fields.Match = *f.Match
+	}
+	if f.Pattern != nil {
+		fields.Pattern = *f.Pattern
+	}
+	if f.TagID != nil {
+		if err := checkTag(ctx, q, user, *f.TagID); err != nil {
+			return Rule{}, err
+		}
+		fields.TagID = *f.TagID
+	}
+	if err := fields.validate(); err != nil {
+		return Rule{}, err
+	}
+	if err := checkPattern(ctx, q, fields); err != nil {
+		return Rule{}, err
+	}
+
+	r, err = q.TagRuleUpdate(ctx, db.TagRuleUpdateParams{
+		UserID:  user.ID,
+		ID:      id,
+		Match:   fields.Match.model(),
+		Pattern: fields.Pattern,
+		TagID:   fields.TagID,
+	})
+	if err != nil {
+		return Rule{}, err
+	}
+	return model(r), nil
+}
+
+func Delete(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Rule, error) {
+	r, err := q.TagRuleDelete(ctx, db.TagRuleDeleteParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Rule{}, api.NoResourceError("rule", id.String())
+	}
+	if err != nil {
+		return Rule{}, err
+	}
+	return model(r), nil
+}
+
+// A Result is what applying a rule did, or would do in a dry run. Count is
+// the number of drops it tagged, and Drops are the oldest of them, up to
+// PreviewLimit.
+type Result struct {
+	Rule  Rule        `json:"rule"`
+	Count int64       `json:"count"`
+	Drops []drop.Drop `json:"drops"`
+}
+
+// Apply tags the user's existing drops with each of their rules, or just one
+// of them if id is set. In a dry run, nothing changes, and the results are
+// what each rule would do on its own.
+func Apply(ctx context.Context, q db.Queryable, user api.User, id *uuid.UUID, dryRun bool) ([]Result, error) {
+	var rs []db.TagRule
+	if id != nil {
+		r, err := find(ctx, q, user, *id)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	} else {
+		var err error
+		rs, err = q.TagRuleList(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]Result, 0, len(rs))
+	for _, r := range rs {
+		if dryRun {
+			ds, n, err := drop.RuleMatching(ctx, q, r, PreviewLimit)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, Result{Rule: model(r), Count: n, Drops: ds})
+			continue
+		}
+
+		ds, err := drop.ApplyRule(ctx, q, r)
+		if err != nil {
+			return nil, err
+		}
+		n := int64(len(ds))
+		if len(ds) > PreviewLimit {
+			ds = ds[:PreviewLimit]
+		}
+		res = append(res, Result{Rule: model(r), Count: n, Drops: ds})
+	}
+	return res, nil
+}
@@ -0,0 +1,135 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/rule"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func TestRule(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	papers, err := tag.Create(ctx, q, user, "papers")
+	require.NoError(t, err)
+	papersID := uuid.FromStringOrNil(papers.ID)
+
+	// Drops from before the rule aren't tagged until it's applied.
+	old, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Old", URL: "https://arxiv.org/abs/1"}, now)
+	require.NoError(t, err)
+	assert.Empty(t, old.Tags)
+
+	r, err := rule.Create(ctx, q, user, rule.Fields{
+		Match:   rule.MatchDomain,
+		Pattern: "arxiv.org",
+		TagID:   papersID,
+	})
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(r.ID)
+
+	rs, err := rule.List(ctx, q, user)
+	require.NoError(t, err)
+	assert.Equal(t, []rule.Rule{r}, rs)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "New", URL: "https://export.arxiv.org/abs/2"}, now)
+	require.NoError(t, err)
+	require.Len(t, d.Tags, 1)
+	assert.Equal(t, papers.ID, d.Tags[0].ID)
+
+	other, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Other", URL: "https://example.com/"}, now)
+	require.NoError(t, err)
+	assert.Empty(t, other.Tags)
+
+	// A dry run doesn't change anything.
+	res, err := rule.Apply(ctx, q, user, &id, true)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(1), res[0].Count)
+	require.Len(t, res[0].Drops, 1)
+	assert.Equal(t, old.ID, res[0].Drops[0].ID)
+	assert.Empty(t, res[0].Drops[0].Tags)
+
+	res, err = rule.Apply(ctx, q, user, nil, false)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(1), res[0].Count)
+	require.Len(t, res[0].Drops, 1)
+	require.Len(t, res[0].Drops[0].Tags, 1)
+
+	// Applying again has nothing left to do.
+	res, err = rule.Apply(ctx, q, user, nil, true)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(0), res[0].Count)
+
+	title := rule.MatchTitle
+	pattern := "deep LEARNING"
+	r, err = rule.Update(ctx, q, user, id, rule.UpdateFields{Match: &title, Pattern: &pattern})
+	require.NoError(t, err)
+	assert.Equal(t, rule.MatchTitle, r.Match)
+
+	d, err = drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Deep reinforcement learning", URL: "https://example.org/"}, now)
+	require.NoError(t, err)
+	require.Len(t, d.Tags, 1)
+
+	deleted, err := rule.Delete(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Equal(t, r, deleted)
+
+	_, err = rule.Get(ctx, q, user, id)
+	assert.ErrorIs(t, err, api.NoResourceError("rule", id.String()))
+}
+
+func TestRuleValidation(t *testing.T) {
+	var (
+		ctx  = apitest.Context(t, 500*time.Millisecond)
+		tx   = apitest.Tx(t, ctx)
+		user = apitest.User(t, ctx, tx)
+		q    = db.New(tx)
+	)
+
+	tg, err := tag.Create(ctx, q, user, "video")
+	require.NoError(t, err)
+	tagID := uuid.FromStringOrNil(tg.ID)
+
+	tests := map[string]rule.Fields{
+		"blank pattern": {Match: rule.MatchDomain, Pattern: " ", TagID: tagID},
+		"bad regexp":    {Match: rule.MatchURL, Pattern: "(", TagID: tagID},
+		// Go accepts named groups and Unicode classes, but Postgres doesn't.
+		"go-only group": {Match: rule.MatchURL, Pattern: "(?P<host>youtube)", TagID: tagID},
+		"go-only class": {Match: rule.MatchURL, Pattern: `\p{L}+`, TagID: tagID},
+		"unknown match": {Match: rule.Match("host"), Pattern: "youtube.com", TagID: tagID},
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := rule.Create(ctx, q, user, f)
+			var aerr api.Error
+			require.ErrorAs(t, err, &aerr)
+			assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+		})
+	}
+
+	// A rejected pattern doesn't spoil the transaction.
+	_, err = rule.Create(ctx, q, user, rule.Fields{Match: rule.MatchURL, Pattern: `youtube\.com/watch`, TagID: tagID})
+	require.NoError(t, err)
+
+	missing := uuid.Must(uuid.NewV4())
+	_, err = rule.Create(ctx, q, user, rule.Fields{Match: rule.MatchDomain, Pattern: "youtube.com", TagID: missing})
+	assert.ErrorIs(t, err, api.NoResourceError("tag", missing.String()))
+}
@@ -9,6 +9,11 @@ import (
 	"github.com/metagram-net/firehose/api"
 	"github.com/metagram-net/firehose/auth"
 	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/policy"
+	"github.com/metagram-net/firehose/rule"
+	"github.com/metagram-net/firehose/stats"
+	"github.com/metagram-net/firehose/tag"
+	"github.com/metagram-net/firehose/view"
 	"github.com/metagram-net/firehose/wellknown"
 )
 
@@ -16,6 +21,11 @@ type Handler struct {
 	WellKnown WellKnown
 	Auth      Auth
 	Drops     Drops
+	Tags      Tags
+	Stats     Stats
+	Policies  Policies
+	Views     Views
+	Rules     Rules
 }
 
 type WellKnown interface {
@@ -28,12 +38,70 @@ type Auth interface {
 
 type Drops interface {
 	Next(ctx api.Context, user api.User) (drop.Drop, error)
+	NextMatching(ctx api.Context, user api.User, body drop.NextBody) (drop.Drop, error)
 	Get(ctx api.Context, user api.User, params drop.GetParams) (drop.Drop, error)
 	List(ctx api.Context, user api.User, body drop.ListBody) (drop.ListResponse, error)
+	Search(ctx api.Context, user api.User, body drop.SearchBody) (drop.SearchResponse, error)
+	History(ctx api.Context, user api.User, body drop.HistoryBody) (drop.HistoryResponse, error)
+	Content(ctx api.Context, user api.User, body drop.ContentBody) (drop.Content, error)
+	Export(ctx api.Context, user api.User) (drop.Export, error)
 	Create(ctx api.Context, user api.User, body drop.CreateBody) (drop.Drop, error)
+	CreateBatch(ctx api.Context, user api.User, body drop.CreateBatchBody) (drop.CreateBatchResponse, error)
 	Update(ctx api.Context, user api.User, body drop.UpdateBody) (drop.Drop, error)
 	Move(ctx api.Context, user api.User, body drop.MoveBody) (drop.Drop, error)
+	Snooze(ctx api.Context, user api.User, body drop.SnoozeBody) (drop.Drop, error)
+	Pin(ctx api.Context, user api.User, body drop.PinBody) (drop.Drop, error)
+	Unpin(ctx api.Context, user api.User, body drop.UnpinBody) (drop.Drop, error)
 	Delete(ctx api.Context, user api.User, body drop.DeleteBody) (drop.Drop, error)
+	BulkMove(ctx api.Context, user api.User, body drop.BulkMoveBody) (drop.BulkResponse, error)
+	BulkTag(ctx api.Context, user api.User, body drop.BulkTagBody) (drop.BulkResponse, error)
+	BulkDelete(ctx api.Context, user api.User, body drop.BulkDeleteBody) (drop.BulkResponse, error)
+	Trash(ctx api.Context, user api.User) (drop.TrashResponse, error)
+	Restore(ctx api.Context, user api.User, body drop.RestoreBody) (drop.Drop, error)
+	EmptyTrash(ctx api.Context, user api.User, body drop.EmptyTrashBody) (drop.EmptyTrashResponse, error)
+	AddHighlight(ctx api.Context, user api.User, body drop.AddHighlightBody) (drop.Highlight, error)
+	EditHighlight(ctx api.Context, user api.User, body drop.EditHighlightBody) (drop.Highlight, error)
+	RemoveHighlight(ctx api.Context, user api.User, body drop.RemoveHighlightBody) (drop.Highlight, error)
+}
+
+type Tags interface {
+	Create(ctx api.Context, user api.User, body tag.CreateBody) (tag.Tag, error)
+	List(ctx api.Context, user api.User, body tag.ListBody) (tag.ListResponse, error)
+	Get(ctx api.Context, user api.User, params tag.GetParams) (tag.Tag, error)
+	Rename(ctx api.Context, user api.User, body tag.RenameBody) (tag.Tag, error)
+	Update(ctx api.Context, user api.User, body tag.UpdateBody) (tag.Tag, error)
+	SetParent(ctx api.Context, user api.User, body tag.SetParentBody) (tag.Tag, error)
+	Merge(ctx api.Context, user api.User, body tag.MergeBody) (tag.MergeResponse, error)
+	Delete(ctx api.Context, user api.User, body tag.DeleteBody) (tag.Tag, error)
+}
+
+type Stats interface {
+	Get(ctx api.Context, user api.User, body stats.Body) (stats.Stats, error)
+}
+
+type Policies interface {
+	Create(ctx api.Context, user api.User, body policy.CreateBody) (policy.Policy, error)
+	List(ctx api.Context, user api.User) (policy.ListResponse, error)
+	Update(ctx api.Context, user api.User, body policy.UpdateBody) (policy.Policy, error)
+	Delete(ctx api.Context, user api.User, body policy.DeleteBody) (policy.Policy, error)
+	Preview(ctx api.Context, user api.User, body policy.PreviewBody) (policy.PreviewResponse, error)
+}
+
+type Views interface {
+	Create(ctx api.Context, user api.User, body view.CreateBody) (view.View, error)
+	List(ctx api.Context, user api.User) (view.ListResponse, error)
+	Update(ctx api.Context, user api.User, body view.UpdateBody) (view.View, error)
+	Delete(ctx api.Context, user api.User, body view.DeleteBody) (view.View, error)
+	ListDrops(ctx api.Context, user api.User, body view.ListDropsBody) (drop.ListResponse, error)
+	NextDrop(ctx api.Context, user api.User, body view.NextDropBody) (drop.Drop, error)
+}
+
+type Rules interface {
+	Create(ctx api.Context, user api.User, body rule.CreateBody) (rule.Rule, error)
+	List(ctx api.Context, user api.User) (rule.ListResponse, error)
+	Update(ctx api.Context, user api.User, body rule.UpdateBody) (rule.Rule, error)
+	Delete(ctx api.Context, user api.User, body rule.DeleteBody) (rule.Rule, error)
+	Apply(ctx api.Context, user api.User, body rule.ApplyBody) (rule.ApplyResponse, error)
 }
 
 func Register(srv *api.Server, h Handler) *mux.Router {
@@ -96,6 +164,33 @@ func Register(srv *api.Server, h Handler) *mux.Router {
 		srv.Respond(w, res, ctx.Close())
 	})
 
+	r.Methods(http.MethodPost).Path("/v1/drops/next-matching").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.NextBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.NextMatching(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
 	r.Methods(http.MethodGet).Path("/v1/drops/get/{id}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, err := srv.Context(r)
 		if err != nil {
@@ -150,6 +245,108 @@ func Register(srv *api.Server, h Handler) *mux.Router {
 		srv.Respond(w, res, ctx.Close())
 	})
 
+	r.Methods(http.MethodPost).Path("/v1/drops/search").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.SearchBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Search(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/history").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.HistoryBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.History(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/content").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.ContentBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Content(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodGet).Path("/v1/drops/export").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Export(ctx, *user)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Stream(w, res, ctx.Close)
+	})
+
 	r.Methods(http.MethodPost).Path("/v1/drops/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, err := srv.Context(r)
 		if err != nil {
@@ -177,6 +374,33 @@ func Register(srv *api.Server, h Handler) *mux.Router {
 		srv.Respond(w, res, ctx.Close())
 	})
 
+	r.Methods(http.MethodPost).Path("/v1/drops/create-batch").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.CreateBatchBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.CreateBatch(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
 	r.Methods(http.MethodPost).Path("/v1/drops/update").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, err := srv.Context(r)
 		if err != nil {
@@ -231,6 +455,87 @@ func Register(srv *api.Server, h Handler) *mux.Router {
 		srv.Respond(w, res, ctx.Close())
 	})
 
+	r.Methods(http.MethodPost).Path("/v1/drops/snooze").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.SnoozeBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Snooze(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/pin").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.PinBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Pin(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/unpin").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.UnpinBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Unpin(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
 	r.Methods(http.MethodPost).Path("/v1/drops/delete").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, err := srv.Context(r)
 		if err != nil {
@@ -258,5 +563,899 @@ func Register(srv *api.Server, h Handler) *mux.Router {
 		srv.Respond(w, res, ctx.Close())
 	})
 
+	r.Methods(http.MethodPost).Path("/v1/drops/bulk/move").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.BulkMoveBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.BulkMove(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/bulk/tag").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.BulkTagBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.BulkTag(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/bulk/delete").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.BulkDeleteBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.BulkDelete(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodGet).Path("/v1/drops/trash").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Trash(ctx, *user)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/restore").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.RestoreBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.Restore(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/empty-trash").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.EmptyTrashBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.EmptyTrash(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/highlights/add").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.AddHighlightBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.AddHighlight(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/highlights/edit").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.EditHighlightBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.EditHighlight(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/drops/highlights/remove").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body drop.RemoveHighlightBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Drops.RemoveHighlight(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.CreateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.Create(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/list").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.ListBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.List(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodGet).Path("/v1/tags/get/{id}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var params tag.GetParams
+		if err := api.FromVars(mux.Vars(r), &params); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.Get(ctx, *user, params)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/rename").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.RenameBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.Rename(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/update").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.UpdateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.Update(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/set-parent").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.SetParentBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.SetParent(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/merge").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.MergeBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.Merge(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/tags/delete").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body tag.DeleteBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Tags.Delete(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/stats").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body stats.Body
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Stats.Get(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/policies/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body policy.CreateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Policies.Create(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodGet).Path("/v1/policies/list").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Policies.List(ctx, *user)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/policies/update").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body policy.UpdateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Policies.Update(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/policies/delete").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body policy.DeleteBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Policies.Delete(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/policies/preview").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body policy.PreviewBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Policies.Preview(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/views/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body view.CreateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Views.Create(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodGet).Path("/v1/views/list").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Views.List(ctx, *user)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/views/update").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body view.UpdateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Views.Update(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/views/delete").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body view.DeleteBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Views.Delete(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/views/drops/list").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body view.ListDropsBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Views.ListDrops(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/views/drops/next").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body view.NextDropBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Views.NextDrop(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/rules/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body rule.CreateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Rules.Create(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodGet).Path("/v1/rules/list").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Rules.List(ctx, *user)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/rules/update").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body rule.UpdateBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Rules.Update(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/rules/delete").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body rule.DeleteBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Rules.Delete(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
+	r.Methods(http.MethodPost).Path("/v1/rules/apply").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := srv.Context(r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		user, err := srv.Authenticate(ctx, r)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		var body rule.ApplyBody
+		if err := api.FromBody(r.Body, &body); err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+
+		res, err := h.Rules.Apply(ctx, *user, body)
+		if err != nil {
+			srv.Respond(w, nil, err)
+			return
+		}
+		srv.Respond(w, res, ctx.Close())
+	})
+
 	return r
 }
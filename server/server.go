@@ -3,22 +3,47 @@ package server
 import (
 	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
 	"github.com/metagram-net/firehose/api"
 	"github.com/metagram-net/firehose/auth"
+	"github.com/metagram-net/firehose/canonical"
 	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/policy"
+	"github.com/metagram-net/firehose/rule"
+	"github.com/metagram-net/firehose/stats"
+	"github.com/metagram-net/firehose/tag"
+	"github.com/metagram-net/firehose/view"
 	"github.com/metagram-net/firehose/wellknown"
 )
 
-func New(log *zap.Logger, db *sql.DB) *mux.Router {
+type Config struct {
+	// CanonicalURLRules are applied to drop URLs in addition to the defaults.
+	CanonicalURLRules canonical.Rules
+
+	// AllowPrivateAddresses lets the metadata fetcher connect to private
+	// networks. See metadata.NewFetcher.
+	AllowPrivateAddresses bool
+
+	// TrashRetention is how long deleted drops stay in the trash before the
+	// worker purges them.
+	TrashRetention time.Duration
+}
+
+func New(log *zap.Logger, db *sql.DB, cfg Config) *mux.Router {
 	srv := api.NewServer(log, db)
 	handler := Handler{
 		WellKnown: wellknown.Handler{},
 		Auth:      auth.Handler{},
-		Drops:     drop.Handler{},
+		Drops:     drop.Handler{URLs: cfg.CanonicalURLRules},
+		Tags:      tag.Handler{},
+		Stats:     stats.Handler{},
+		Policies:  policy.Handler{},
+		Views:     view.Handler{},
+		Rules:     rule.Handler{},
 	}
 
 	router := Register(srv, handler)
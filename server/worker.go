@@ -0,0 +1,38 @@
+package server
+
+import (
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/metadata"
+	"github.com/metagram-net/firehose/worker"
+)
+
+// NewWorker creates a worker with all the background jobs registered.
+func NewWorker(log *zap.Logger, sqldb *sql.DB, cfg Config) *worker.Worker {
+	w := worker.New(log, sqldb)
+
+	fetcher := metadata.NewFetcher(cfg.AllowPrivateAddresses)
+	w.Every("drop-metadata", 5*time.Second, func(ctx api.Context) (bool, error) {
+		return drop.FetchMetadata(ctx, db.New(ctx.Tx), ctx.Log, fetcher, ctx.Clock.Now())
+	})
+	w.Every("drop-content", 5*time.Second, func(ctx api.Context) (bool, error) {
+		return drop.FetchContent(ctx, db.New(ctx.Tx), ctx.Log, fetcher, ctx.Clock.Now())
+	})
+	w.Every("drop-wake", time.Minute, func(ctx api.Context) (bool, error) {
+		return false, drop.Wake(ctx, db.New(ctx.Tx), ctx.Clock.Now())
+	})
+	w.Every("drop-purge", time.Hour, func(ctx api.Context) (bool, error) {
+		return drop.Purge(ctx, db.New(ctx.Tx), ctx.Clock.Now().Add(-cfg.TrashRetention))
+	})
+	w.Every("drop-expire", time.Hour, func(ctx api.Context) (bool, error) {
+		return drop.Expire(ctx, db.New(ctx.Tx), ctx.Clock.Now())
+	})
+
+	return w
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// MaxPeriods is the longest trend that can be requested.
+const MaxPeriods = 366
+
+type Handler struct{}
+
+type Body struct {
+	// Interval is the length of each period in the trend. The default is a
+	// day.
+	Interval Interval `json:"interval,omitempty"`
+	// Periods is the number of periods in the trend, including the current
+	// one.
+	Periods *int32 `json:"periods,omitempty"`
+}
+
+func (b Body) Validate() error {
+	switch b.Interval {
+	case "", IntervalDay, IntervalWeek:
+	default:
+		return api.ValidationError("interval", b.Interval, fmt.Sprintf("must be one of: %s", strings.Join(IntervalValueStrings(), ", ")))
+	}
+	if p := b.Periods; p != nil && (*p < 1 || *p > MaxPeriods) {
+		return api.ValidationError("periods", fmt.Sprint(*p), fmt.Sprintf("must be between 1 and %d", MaxPeriods))
+	}
+	return nil
+}
+
+func (Handler) Get(ctx api.Context, u api.User, body Body) (Stats, error) {
+	q := db.New(ctx.Tx)
+	now := ctx.Clock.Now()
+
+	interval := body.Interval
+	if interval == "" {
+		interval = IntervalDay
+	}
+	periods := interval.defaultPeriods()
+	if body.Periods != nil {
+		periods = *body.Periods
+	}
+	return Get(ctx, q, u, interval, periods, now)
+}
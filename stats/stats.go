@@ -0,0 +1,294 @@
+// Package stats summarizes how a user is keeping up with their drops.
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+// An Interval is the length of one period in a trend.
+type Interval string
+
+const (
+	IntervalDay  Interval = "day"
+	IntervalWeek Interval = "week"
+)
+
+// IntervalValueStrings returns all valid values of the enum as strings.
+func IntervalValueStrings() []string {
+	return []string{
+		string(IntervalDay),
+		string(IntervalWeek),
+	}
+}
+
+// defaultPeriods is the length of the trend if none is requested: two weeks
+// of days, or about a quarter of weeks.
+func (i Interval) defaultPeriods() int32 {
+	if i == IntervalWeek {
+		return 12
+	}
+	return 14
+}
+
+// Implement pflag.Value
+
+func (i *Interval) String() string {
+	return string(*i)
+}
+
+func (i *Interval) Set(s string) error {
+	switch Interval(s) {
+	case IntervalDay, IntervalWeek:
+		*i = Interval(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown interval: %s (expected one of: %s)", s, strings.Join(IntervalValueStrings(), ", "))
+	}
+}
+
+func (*Interval) Type() string {
+	return "interval"
+}
+
+// Counts are the numbers of drops in each status. Like drop.List, snoozed
+// drops that have woken up count as unread.
+type Counts struct {
+	Unread  int64 `json:"unread"`
+	Read    int64 `json:"read"`
+	Saved   int64 `json:"saved"`
+	Snoozed int64 `json:"snoozed"`
+	Expired int64 `json:"expired"`
+}
+
+// Total is the number of drops in any status.
+func (c Counts) Total() int64 {
+	return c.Unread + c.Read + c.Saved + c.Snoozed + c.Expired
+}
+
+// A Period is one step in a trend. Drops are consumed when they're moved to
+// read or saved, so drops that have been moved again since then only count
+// in the latest period.
+type Period struct {
+	Start    time.Time `json:"start"`
+	Created  int64     `json:"created"`
+	Consumed int64     `json:"consumed"`
+}
+
+type TagStats struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Counts Counts `json:"counts"`
+}
+
+type Stats struct {
+	Counts Counts `json:"counts"`
+	// OldestUnread is when the oldest unread drop was moved to unread, and
+	// OldestUnreadSeconds is how long ago that was. Both are null if there
+	// are no unread drops.
+	OldestUnread        *time.Time `json:"oldest_unread,omitempty"`
+	OldestUnreadSeconds *int64     `json:"oldest_unread_seconds,omitempty"`
+
+	Interval Interval   `json:"interval"`
+	Trend    []Period   `json:"trend"`
+	Tags     []TagStats `json:"tags"`
+}
+
+// Get summarizes the user's drops, with a trend over the last few periods
+// (including the current one). Drops in the trash aren't counted.
+func Get(ctx context.Context, q db.Queryable, user api.User, interval Interval, periods int32, now time.Time) (Stats, error) {
+	s := Stats{Interval: interval}
+
+	var oldest sql.NullTime
+	err := scanRow(ctx, q, countsQuery(now).
+		Column(sq.Expr("min(drops.moved_at) FILTER (WHERE "+unreadCond+")", now)).
+		From("drops").
+		Where(sq.Eq{
+			"drops.user_id":    user.ID,
+			"drops.deleted_at": nil,
+		}),
+		&s.Counts.Unread, &s.Counts.Read, &s.Counts.Saved, &s.Counts.Snoozed, &s.Counts.Expired, &oldest)
+	if err != nil {
+		return Stats{}, err
+	}
+	if oldest.Valid {
+		age := int64(now.Sub(oldest.Time) / time.Second)
+		s.OldestUnread = &oldest.Time
+		s.OldestUnreadSeconds = &age
+	}
+
+	s.Trend, err = trend(ctx, q, user, interval, periods, now)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	s.Tags, err = TagCounts(ctx, q, user, now)
+	if err != nil {
+		return Stats{}, err
+	}
+	return s, nil
+}
+
+// unreadCond matches unread drops, including snoozed drops that have woken
+// up. It takes the current time as an argument.
+const unreadCond = "drops.status = 'unread' OR (drops.status = 'snoozed' AND drops.snooze_until <= ?)"
+
+// countsQuery selects the columns of Counts, in order.
+func countsQuery(now time.Time) sq.SelectBuilder {
+	return db.Pq.
+		Select().
+		Column(sq.Expr("count(drops.id) FILTER (WHERE "+unreadCond+")", now)).
+		Column("count(drops.id) FILTER (WHERE drops.status = 'read')").
+		Column("count(drops.id) FILTER (WHERE drops.status = 'saved')").
+		Column(sq.Expr("count(drops.id) FILTER (WHERE drops.status = 'snoozed' AND drops.snooze_until > ?)", now)).
+		Column("count(drops.id) FILTER (WHERE drops.status = 'expired')")
+}
+
+// TagCounts counts the drops with each of the user's tags, including tags
+// without any drops. Drops in the trash aren't counted.
+func TagCounts(ctx context.Context, q db.Queryable, user api.User, now time.Time) ([]TagStats, error) {
+	// Left joins, so tags without any drops are included too.
+	query, args, err := countsQuery(now).
+		Columns("tags.id", "tags.name").
+		From("tags").
+		LeftJoin("drop_tags ON drop_tags.tag_id = tags.id").
+		LeftJoin("drops ON drops.id = drop_tags.drop_id AND drops.deleted_at IS NULL").
+		Where(sq.Eq{"tags.user_id": user.ID}).
+		GroupBy("tags.id", "tags.name").
+		OrderBy("tags.name asc", "tags.id asc").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ts := make([]TagStats, 0)
+	for rows.Next() {
+		var (
+			t  TagStats
+			id uuid.UUID
+			c  = &t.Counts
+		)
+		if err := rows.Scan(&c.Unread, &c.Read, &c.Saved, &c.Snoozed, &c.Expired, &id, &t.Name); err != nil {
+			return nil, err
+		}
+		t.ID = id.String()
+		ts = append(ts, t)
+	}
+	return ts, rows.Err()
+}
+
+// trend counts the drops created and consumed in each period.
+func trend(ctx context.Context, q db.Queryable, user api.User, interval Interval, periods int32, now time.Time) ([]Period, error) {
+	start := truncate(now, interval)
+	starts := make([]time.Time, periods)
+	for i := range starts {
+		starts[len(starts)-1-i] = step(start, interval, -i)
+	}
+
+	created, err := countBy(ctx, q, interval, "drops.created_at", starts[0], sq.Eq{
+		"drops.user_id":    user.ID,
+		"drops.deleted_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	consumed, err := countBy(ctx, q, interval, "drops.moved_at", starts[0], sq.Eq{
+		"drops.user_id":    user.ID,
+		"drops.deleted_at": nil,
+		"drops.status":     []db.DropStatus{db.DropStatusRead, db.DropStatusSaved},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make([]Period, 0, len(starts))
+	for _, s := range starts {
+		k := s.Format(dateKey)
+		ps = append(ps, Period{
+			Start:    s,
+			Created:  created[k],
+			Consumed: consumed[k],
+		})
+	}
+	return ps, nil
+}
+
+const dateKey = "2006-01-02"
+
+// countBy counts the matching drops by the period their timestamp column is
+// in, keyed by the date the period starts on.
+func countBy(ctx context.Context, q db.Queryable, interval Interval, col string, since time.Time, where sq.Sqlizer) (map[string]int64, error) {
+	query, args, err := db.Pq.
+		Select().
+		Column(sq.Expr("date_trunc(?, "+col+") AS period", string(interval))).
+		Column("count(*)").
+		From("drops").
+		Where(where).
+		Where(sq.GtOrEq{col: since}).
+		GroupBy("period").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var (
+			p time.Time
+			n int64
+		)
+		if err := rows.Scan(&p, &n); err != nil {
+			return nil, err
+		}
+		counts[p.Format(dateKey)] = n
+	}
+	return counts, rows.Err()
+}
+
+// truncate returns the start of the period that t is in. Like Postgres's
+// date_trunc, weeks start on Monday.
+func truncate(t time.Time, interval Interval) time.Time {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if interval == IntervalWeek {
+		d = d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	}
+	return d
+}
+
+// step moves the start of a period n periods forward (or back, if n is
+// negative).
+func step(t time.Time, interval Interval, n int) time.Time {
+	if interval == IntervalWeek {
+		return t.AddDate(0, 0, 7*n)
+	}
+	return t.AddDate(0, 0, n)
+}
+
+func scanRow(ctx context.Context, q db.Queryable, qq sq.SelectBuilder, dest ...interface{}) error {
+	query, args, err := qq.ToSql()
+	if err != nil {
+		return err
+	}
+	return q.QueryRowContext(ctx, query, args...).Scan(dest...)
+}
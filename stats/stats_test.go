@@ -0,0 +1,80 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/stats"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func TestGet(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	ml, err := tag.Create(ctx, q, user, "ml")
+	require.NoError(t, err)
+	_, err = tag.Create(ctx, q, user, "unused")
+	require.NoError(t, err)
+
+	create := func(url string, tags []uuid.UUID, at time.Time) uuid.UUID {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: url, TagIDs: tags}, at)
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(d.ID)
+	}
+	mlIDs := []uuid.UUID{uuid.FromStringOrNil(ml.ID)}
+	old := create("https://example.com/old", mlIDs, now.AddDate(0, 0, -3))
+	create("https://example.com/new", nil, now)
+	read := create("https://example.com/read", mlIDs, now.AddDate(0, 0, -1))
+	_, err = drop.Move(ctx, q, user, read, drop.StatusRead, now)
+	require.NoError(t, err)
+	trashed := create("https://example.com/trashed", nil, now)
+	_, err = drop.Delete(ctx, q, user, trashed, now)
+	require.NoError(t, err)
+
+	s, err := stats.Get(ctx, q, user, stats.IntervalDay, 7, now)
+	require.NoError(t, err)
+	assert.Equal(t, stats.Counts{Unread: 2, Read: 1}, s.Counts)
+
+	oldDrop, err := drop.Get(ctx, q, user, old)
+	require.NoError(t, err)
+	require.NotNil(t, s.OldestUnread)
+	assert.True(t, oldDrop.MovedAt.Equal(*s.OldestUnread))
+	require.NotNil(t, s.OldestUnreadSeconds)
+	assert.Equal(t, int64(3*24*60*60), *s.OldestUnreadSeconds)
+
+	require.Len(t, s.Trend, 7)
+	today := s.Trend[6]
+	assert.Equal(t, int64(1), today.Created)
+	assert.Equal(t, int64(1), today.Consumed)
+	assert.Equal(t, int64(1), s.Trend[5].Created)
+	assert.Equal(t, int64(1), s.Trend[3].Created)
+
+	require.Len(t, s.Tags, 2)
+	assert.Equal(t, "ml", s.Tags[0].Name)
+	assert.Equal(t, stats.Counts{Unread: 1, Read: 1}, s.Tags[0].Counts)
+	assert.Equal(t, "unused", s.Tags[1].Name)
+	assert.Equal(t, stats.Counts{}, s.Tags[1].Counts)
+}
+
+func TestBodyValidate(t *testing.T) {
+	zero, many := int32(0), int32(stats.MaxPeriods+1)
+	assert.NoError(t, stats.Body{}.Validate())
+	assert.NoError(t, stats.Body{Interval: stats.IntervalWeek}.Validate())
+	assert.Error(t, stats.Body{Interval: "month"}.Validate())
+	assert.Error(t, stats.Body{Periods: &zero}.Validate())
+	assert.Error(t, stats.Body{Periods: &many}.Validate())
+}
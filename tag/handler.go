@@ -0,0 +1,200 @@
+package tag
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+)
+
+type ID uuid.UUID
+
+func (id *ID) FromParam(s string) error {
+	u, err := uuid.FromString(s)
+	*id = ID(u)
+	return err
+}
+
+func (id ID) UUID() uuid.UUID {
+	return uuid.UUID(id)
+}
+
+type Handler struct{}
+
+type CreateBody struct {
+	Name        string     `json:"name,omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	Color       string     `json:"color,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+func (b CreateBody) Validate() error {
+	if err := validateName(b.Name); err != nil {
+		return err
+	}
+	return validateColor(b.Color)
+}
+
+func (Handler) Create(ctx api.Context, u api.User, body CreateBody) (Tag, error) {
+	q := db.New(ctx.Tx)
+	t, err := Create(ctx, q, u, body.Name)
+	if err != nil {
+		return Tag{}, err
+	}
+	id := uuid.FromStringOrNil(t.ID)
+	if body.Color != "" || body.Description != "" {
+		t, err = Update(ctx, q, u, id, UpdateFields{
+			Color:       &body.Color,
+			Description: &body.Description,
+		})
+		if err != nil {
+			return Tag{}, err
+		}
+	}
+	if body.ParentID != nil {
+		return SetParent(ctx, q, u, id, body.ParentID)
+	}
+	return t, nil
+}
+
+type ListBody struct {
+	// Sort is name (the default) or usage.
+	Sort   Sort `json:"sort,omitempty"`
+	Unused bool `json:"unused,omitempty"`
+}
+
+func (b ListBody) Validate() error {
+	if b.Sort != "" && !b.Sort.valid() {
+		return api.ValidationError("sort", b.Sort, fmt.Sprintf("must be one of: %s", strings.Join(SortValueStrings(), ", ")))
+	}
+	return nil
+}
+
+type ListResponse struct {
+	Tags []Tag  `json:"tags"`
+	Tree []Node `json:"tree"`
+}
+
+func (Handler) List(ctx api.Context, u api.User, body ListBody) (ListResponse, error) {
+	q := db.New(ctx.Tx)
+	ts, err := ListCounts(ctx, q, u, ListOptions{
+		Sort:   body.Sort,
+		Unused: body.Unused,
+	}, ctx.Clock.Now())
+	if err != nil {
+		return ListResponse{}, err
+	}
+	return ListResponse{Tags: ts, Tree: Tree(ts)}, nil
+}
+
+type GetParams struct {
+	ID ID `var:"id"`
+}
+
+func (Handler) Get(ctx api.Context, u api.User, params GetParams) (Tag, error) {
+	q := db.New(ctx.Tx)
+	return Get(ctx, q, u, params.ID.UUID())
+}
+
+type RenameBody struct {
+	ID   uuid.UUID `json:"id,omitempty"`
+	Name string    `json:"name,omitempty"`
+}
+
+func (b RenameBody) Validate() error {
+	return validateName(b.Name)
+}
+
+func (Handler) Rename(ctx api.Context, u api.User, body RenameBody) (Tag, error) {
+	q := db.New(ctx.Tx)
+	return Rename(ctx, q, u, body.ID, body.Name)
+}
+
+type UpdateBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+	// Color and Description replace the tag's color and description. Set
+	// them to the empty string to clear them.
+	Color       *string `json:"color,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+func (b UpdateBody) Validate() error {
+	if b.Color != nil {
+		return validateColor(*b.Color)
+	}
+	return nil
+}
+
+func (Handler) Update(ctx api.Context, u api.User, body UpdateBody) (Tag, error) {
+	q := db.New(ctx.Tx)
+	return Update(ctx, q, u, body.ID, UpdateFields{
+		Color:       body.Color,
+		Description: body.Description,
+	})
+}
+
+type SetParentBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+	// ParentID is the new parent. Leave it out to move the tag to the top
+	// level.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+func (Handler) SetParent(ctx api.Context, u api.User, body SetParentBody) (Tag, error) {
+	q := db.New(ctx.Tx)
+	return SetParent(ctx, q, u, body.ID, body.ParentID)
+}
+
+type MergeBody struct {
+	TargetID  uuid.UUID   `json:"target_id,omitempty"`
+	SourceIDs []uuid.UUID `json:"source_ids"`
+}
+
+func (b MergeBody) Validate() error {
+	if len(b.SourceIDs) == 0 {
+		return api.ValidationError("source_ids", "", "must not be empty")
+	}
+	return nil
+}
+
+type MergeResponse struct {
+	Tag    Tag   `json:"tag"`
+	Merged []Tag `json:"merged"`
+}
+
+func (Handler) Merge(ctx api.Context, u api.User, body MergeBody) (MergeResponse, error) {
+	q := db.New(ctx.Tx)
+	t, merged, err := Merge(ctx, q, u, body.TargetID, body.SourceIDs)
+	return MergeResponse{Tag: t, Merged: merged}, err
+}
+
+type DeleteBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Delete(ctx api.Context, u api.User, body DeleteBody) (Tag, error) {
+	q := db.New(ctx.Tx)
+	return Delete(ctx, q, u, body.ID)
+}
+
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return api.ValidationError("name", name, "must not be blank")
+	}
+	return nil
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateColor checks for a hex color like #1e90ff. Blank colors are fine,
+// since they clear the color.
+func validateColor(color string) error {
+	if color != "" && !colorPattern.MatchString(color) {
+		return api.ValidationError("color", color, "must be a hex color like #1e90ff")
+	}
+	return nil
+}
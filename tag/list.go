@@ -0,0 +1,108 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/stats"
+)
+
+var ErrUnknownSort = errors.New("unknown sort")
+
+// A Sort is the order to list tags in.
+type Sort string
+
+const (
+	// SortName lists tags by name. This is the default.
+	SortName Sort = "name"
+	// SortUsage lists the most used tags first, counting drops in any
+	// status.
+	SortUsage Sort = "usage"
+)
+
+// SortValueStrings returns all valid values of the enum as strings.
+func SortValueStrings() []string {
+	return []string{
+		string(SortName),
+		string(SortUsage),
+	}
+}
+
+func (s Sort) valid() bool {
+	for _, v := range SortValueStrings() {
+		if string(s) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement pflag.Value
+
+func (s *Sort) String() string {
+	return string(*s)
+}
+
+func (s *Sort) Set(str string) error {
+	if !Sort(str).valid() {
+		return fmt.Errorf("%w: %s (expected one of: %s)", ErrUnknownSort, str, strings.Join(SortValueStrings(), ", "))
+	}
+	*s = Sort(str)
+	return nil
+}
+
+func (*Sort) Type() string {
+	return "sort"
+}
+
+type ListOptions struct {
+	Sort Sort
+	// Unused lists only the tags that no drops have, not counting drops in
+	// the trash.
+	Unused bool
+}
+
+// ListCounts lists the user's tags along with how many drops in each status
+// have them.
+func ListCounts(ctx context.Context, q db.Queryable, user api.User, opts ListOptions, now time.Time) ([]Tag, error) {
+	ts, err := List(ctx, q, user)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := stats.TagCounts(ctx, q, user, now)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]stats.Counts, len(cs))
+	for _, c := range cs {
+		counts[c.ID] = c.Counts
+	}
+
+	res := make([]Tag, 0, len(ts))
+	for _, t := range ts {
+		c := counts[t.ID]
+		if opts.Unused && c.Total() > 0 {
+			continue
+		}
+		t.Counts = &c
+		res = append(res, t)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if opts.Sort == SortUsage && a.Counts.Total() != b.Counts.Total() {
+			return a.Counts.Total() > b.Counts.Total()
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	return res, nil
+}
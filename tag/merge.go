@@ -0,0 +1,103 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+)
+
+// Merge moves every use of the source tags to the target and then deletes the
+// sources. Drops that had more than one of the tags end up with the target
+// once, the children of the sources become children of the target, and tag
+// rules for the sources tag with the target instead.
+//
+// Every drop that had a source tag gets a tag event in its history.
+//
+// This returns the target and the deleted sources. It's an error to merge a
+// tag into one of its own descendants.
+func Merge(ctx context.Context, q db.Queryable, user api.User, target uuid.UUID, sources []uuid.UUID) (Tag, []Tag, error) {
+	ts, err := q.TagList(ctx, user.ID)
+	if err != nil {
+		return Tag{}, nil, err
+	}
+	parents := make(map[uuid.UUID]uuid.NullUUID)
+	for _, t := range ts {
+		parents[t.ID] = t.ParentID
+	}
+	if _, ok := parents[target]; !ok {
+		return Tag{}, nil, api.NoResourceError("tag", target.String())
+	}
+
+	var ids []uuid.UUID
+	isSource := make(map[uuid.UUID]bool)
+	for _, id := range sources {
+		if id == target {
+			return Tag{}, nil, api.ValidationError("source_ids", id.String(), "can't merge a tag into itself")
+		}
+		if _, ok := parents[id]; !ok {
+			return Tag{}, nil, api.NoResourceError("tag", id.String())
+		}
+		if !isSource[id] {
+			isSource[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for p := parents[target]; p.Valid; p = parents[p.UUID] {
+		if isSource[p.UUID] {
+			return Tag{}, nil, api.ValidationError("target_id", target.String(), "is a descendant of a source tag")
+		}
+	}
+
+	err = drop.Retagged(ctx, q, user, ids, func() error {
+		_, err := q.DropTagsMergeDuplicates(ctx, db.DropTagsMergeDuplicatesParams{
+			SourceIds: ids,
+			TargetID:  target,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.DropTagsRepoint(ctx, db.DropTagsRepointParams{
+			TargetID:  target,
+			SourceIds: ids,
+		})
+		return err
+	})
+	if err != nil {
+		return Tag{}, nil, err
+	}
+	_, err = q.TagRulesRetag(ctx, db.TagRulesRetagParams{
+		UserID:    user.ID,
+		TargetID:  target,
+		SourceIds: ids,
+	})
+	if err != nil {
+		return Tag{}, nil, err
+	}
+	_, err = q.TagsReparent(ctx, db.TagsReparentParams{
+		UserID:       user.ID,
+		ParentID:     uuid.NullUUID{UUID: target, Valid: true},
+		OldParentIds: ids,
+	})
+	if err != nil {
+		return Tag{}, nil, err
+	}
+
+	merged := make([]Tag, 0, len(ids))
+	for _, id := range ids {
+		t, err := q.TagDelete(ctx, db.TagDeleteParams{
+			UserID: user.ID,
+			ID:     id,
+		})
+		if err != nil {
+			return Tag{}, nil, err
+		}
+		merged = append(merged, model(t))
+	}
+
+	t, err := Get(ctx, q, user, target)
+	return t, merged, err
+}
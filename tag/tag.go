@@ -0,0 +1,273 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/stats"
+)
+
+type Tag struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	ParentID    *string `json:"parent_id,omitempty"`
+	Color       string  `json:"color,omitempty"`
+	Description string  `json:"description,omitempty"`
+	// Counts are only set when listing tags.
+	Counts *stats.Counts `json:"counts,omitempty"`
+}
+
+func model(t db.Tag) Tag {
+	m := Tag{
+		ID:          t.ID.String(),
+		Name:        t.Name,
+		Color:       t.Color.String,
+		Description: t.Description.String,
+	}
+	if t.ParentID.Valid {
+		id := t.ParentID.UUID.String()
+		m.ParentID = &id
+	}
+	return m
+}
+
+// Create creates a tag. Tag names are unique, so this returns a duplicate
+// error if the user already has a tag with the name.
+func Create(ctx context.Context, q db.Queryable, user api.User, name string) (Tag, error) {
+	if err := checkName(ctx, q, user, name, uuid.Nil); err != nil {
+		return Tag{}, err
+	}
+	t, err := q.TagCreate(ctx, db.TagCreateParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+	return model(t), nil
+}
+
+func List(ctx context.Context, q db.Queryable, user api.User) ([]Tag, error) {
+	ts, err := q.TagList(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list of tags should never be nil/null, so always make the slice.
+	res := make([]Tag, 0)
+	for _, t := range ts {
+		res = append(res, model(t))
+	}
+	return res, nil
+}
+
+func Get(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Tag, error) {
+	t, err := q.TagFind(ctx, db.TagFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tag{}, api.NoResourceError("tag", id.String())
+	}
+	if err != nil {
+		return Tag{}, err
+	}
+	return model(t), nil
+}
+
+// checkName returns a duplicate error if the user already has a tag other than
+// self with the name.
+func checkName(ctx context.Context, q db.Queryable, user api.User, name string, self uuid.UUID) error {
+	ts, err := q.TagFindByNames(ctx, db.TagFindByNamesParams{
+		UserID: user.ID,
+		Names:  []string{name},
+	})
+	if err != nil {
+		return err
+	}
+	if len(ts) > 0 && ts[0].ID != self {
+		return api.DuplicateError("tag", ts[0].ID.String(), model(ts[0]))
+	}
+	return nil
+}
+
+func Rename(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, name string) (Tag, error) {
+	if err := checkName(ctx, q, user, name, id); err != nil {
+		return Tag{}, err
+	}
+	t, err := q.TagMove(ctx, db.TagMoveParams{
+		UserID: user.ID,
+		ID:     id,
+		Name:   name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tag{}, api.NoResourceError("tag", id.String())
+	}
+	if err != nil {
+		return Tag{}, err
+	}
+	return model(t), nil
+}
+
+type UpdateFields struct {
+	// Color and Description replace the tag's color and description. Set
+	// them to the empty string to clear them.
+	Color       *string
+	Description *string
+}
+
+// Update changes the fields of a tag that are set.
+func Update(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, f UpdateFields) (Tag, error) {
+	t, err := q.TagFind(ctx, db.TagFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tag{}, api.NoResourceError("tag", id.String())
+	}
+	if err != nil {
+		return Tag{}, err
+	}
+
+	if f.Color != nil {
+		t.Color = nullString(*f.Color)
+	}
+	if f.Description != nil {
+		t.Description = nullString(*f.Description)
+	}
+	t, err = q.TagUpdate(ctx, db.TagUpdateParams{
+		UserID:      user.ID,
+		ID:          id,
+		Color:       t.Color,
+		Description: t.Description,
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+	return model(t), nil
+}
+
+// nullString treats the empty string as null.
+func nullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return db.NullString(&s)
+}
+
+// SetParent moves the tag under the parent, or to the top level if the parent
+// is nil. A tag can't be its own ancestor.
+func SetParent(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, parent *uuid.UUID) (Tag, error) {
+	var parentID uuid.NullUUID
+	if parent != nil {
+		ts, err := q.TagList(ctx, user.ID)
+		if err != nil {
+			return Tag{}, err
+		}
+		parents := make(map[uuid.UUID]uuid.NullUUID)
+		for _, t := range ts {
+			parents[t.ID] = t.ParentID
+		}
+		if _, ok := parents[*parent]; !ok {
+			return Tag{}, api.NoResourceError("tag", parent.String())
+		}
+		// Walk up from the new parent. If this reaches the tag, the tag
+		// would be its own ancestor.
+		for p := (uuid.NullUUID{UUID: *parent, Valid: true}); p.Valid; p = parents[p.UUID] {
+			if p.UUID == id {
+				return Tag{}, api.ValidationError("parent_id", parent.String(), "would make a cycle")
+			}
+		}
+		parentID = uuid.NullUUID{UUID: *parent, Valid: true}
+	}
+
+	t, err := q.TagSetParent(ctx, db.TagSetParentParams{
+		UserID:   user.ID,
+		ID:       id,
+		ParentID: parentID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tag{}, api.NoResourceError("tag", id.String())
+	}
+	if err != nil {
+		return Tag{}, err
+	}
+	return model(t), nil
+}
+
+// A Node is a tag with its children.
+type Node struct {
+	Tag
+	Children []Node `json:"children"`
+}
+
+// Tree arranges the tags by their parents. Tags whose parent isn't in the
+// list are at the top level. Siblings are sorted by name.
+func Tree(tags []Tag) []Node {
+	ids := make(map[string]bool)
+	for _, t := range tags {
+		ids[t.ID] = true
+	}
+	children := make(map[string][]Tag)
+	for _, t := range tags {
+		parent := ""
+		if t.ParentID != nil && ids[*t.ParentID] {
+			parent = *t.ParentID
+		}
+		children[parent] = append(children[parent], t)
+	}
+
+	var build func(parent string) []Node
+	build = func(parent string) []Node {
+		ts := children[parent]
+		sort.Slice(ts, func(i, j int) bool {
+			if ts[i].Name != ts[j].Name {
+				return ts[i].Name < ts[j].Name
+			}
+			return ts[i].ID < ts[j].ID
+		})
+		// The children should never be nil/null, so always make the slice.
+		nodes := make([]Node, 0, len(ts))
+		for _, t := range ts {
+			nodes = append(nodes, Node{Tag: t, Children: build(t.ID)})
+		}
+		return nodes
+	}
+	return build("")
+}
+
+// Delete removes a tag from every drop, recording that in their histories, and
+// then deletes it.
+func Delete(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (Tag, error) {
+	// Detach the tag from every drop first. These references need to be
+	// removed before the tag can be deleted.
+	err := drop.Retagged(ctx, q, user, []uuid.UUID{id}, func() error {
+		_, err := q.DropTagsDetach(ctx, db.DropTagsDetachParams{
+			UserID: user.ID,
+			ID:     id,
+		})
+		return err
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+
+	t, err := q.TagDelete(ctx, db.TagDeleteParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tag{}, api.NoResourceError("tag", id.String())
+	}
+	if err != nil {
+		return Tag{}, err
+	}
+	return model(t), nil
+}
@@ -0,0 +1,270 @@
+package tag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/stats"
+	"github.com/metagram-net/firehose/tag"
+)
+
+func TestDelete(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	tg, err := tag.Create(ctx, q, user, "reading")
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(tg.ID)
+
+	d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: "Example Dot Net", URL: "https://example.net", TagIDs: []uuid.UUID{id}}, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, d.Tags, 1)
+
+	deleted, err := tag.Delete(ctx, q, user, id)
+	require.NoError(t, err)
+	assert.Equal(t, tg, deleted)
+
+	d, err = drop.Get(ctx, q, user, uuid.FromStringOrNil(d.ID))
+	require.NoError(t, err)
+	assert.Empty(t, d.Tags)
+
+	es, err := drop.History(ctx, q, user, uuid.FromStringOrNil(d.ID))
+	require.NoError(t, err)
+	require.Len(t, es, 2)
+	assert.Equal(t, drop.EventTag, es[1].Kind)
+	assert.Len(t, es[1].Before.Tags, 1)
+	assert.Empty(t, es[1].After.Tags)
+
+	_, err = tag.Get(ctx, q, user, id)
+	assert.ErrorIs(t, err, api.NoResourceError("tag", id.String()))
+}
+
+func TestCreateDuplicate(t *testing.T) {
+	var (
+		ctx  = apitest.Context(t, 500*time.Millisecond)
+		tx   = apitest.Tx(t, ctx)
+		user = apitest.User(t, ctx, tx)
+		q    = db.New(tx)
+	)
+
+	golang, err := tag.Create(ctx, q, user, "go")
+	require.NoError(t, err)
+	other, err := tag.Create(ctx, q, user, "golang")
+	require.NoError(t, err)
+
+	var aerr api.Error
+	_, err = tag.Create(ctx, q, user, "go")
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("duplicate_resource"), aerr.Code)
+	_, err = tag.Rename(ctx, q, user, uuid.FromStringOrNil(other.ID), "go")
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("duplicate_resource"), aerr.Code)
+
+	// Keeping the same name isn't a duplicate.
+	_, err = tag.Rename(ctx, q, user, uuid.FromStringOrNil(golang.ID), "go")
+	assert.NoError(t, err)
+}
+
+func TestSetParent(t *testing.T) {
+	var (
+		ctx  = apitest.Context(t, 500*time.Millisecond)
+		tx   = apitest.Tx(t, ctx)
+		user = apitest.User(t, ctx, tx)
+		q    = db.New(tx)
+	)
+
+	create := func(name string) uuid.UUID {
+		tg, err := tag.Create(ctx, q, user, name)
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(tg.ID)
+	}
+	code := create("code")
+	golang := create("go")
+	generics := create("generics")
+
+	_, err := tag.SetParent(ctx, q, user, golang, &code)
+	require.NoError(t, err)
+	tg, err := tag.SetParent(ctx, q, user, generics, &golang)
+	require.NoError(t, err)
+	require.NotNil(t, tg.ParentID)
+	assert.Equal(t, golang.String(), *tg.ParentID)
+
+	var aerr api.Error
+	_, err = tag.SetParent(ctx, q, user, code, &generics)
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+	_, err = tag.SetParent(ctx, q, user, code, &code)
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+
+	tg, err = tag.SetParent(ctx, q, user, generics, nil)
+	require.NoError(t, err)
+	assert.Nil(t, tg.ParentID)
+}
+
+func TestTree(t *testing.T) {
+	parent := func(id string) *string { return &id }
+	tags := []tag.Tag{
+		{ID: "3", Name: "go", ParentID: parent("1")},
+		{ID: "1", Name: "code"},
+		{ID: "4", Name: "generics", ParentID: parent("3")},
+		{ID: "2", Name: "books"},
+		{ID: "5", Name: "orphan", ParentID: parent("9")},
+		{ID: "6", Name: "c", ParentID: parent("1")},
+	}
+
+	assert.Equal(t, []tag.Node{
+		{Tag: tags[3], Children: []tag.Node{}},
+		{Tag: tags[1], Children: []tag.Node{
+			{Tag: tags[5], Children: []tag.Node{}},
+			{Tag: tags[0], Children: []tag.Node{
+				{Tag: tags[2], Children: []tag.Node{}},
+			}},
+		}},
+		{Tag: tags[4], Children: []tag.Node{}},
+	}, tag.Tree(tags))
+}
+
+func TestMerge(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+	)
+
+	create := func(name string) uuid.UUID {
+		tg, err := tag.Create(ctx, q, user, name)
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(tg.ID)
+	}
+	golang := create("go")
+	other := create("golang")
+	lang := create("go-lang")
+	generics := create("generics")
+	_, err := tag.SetParent(ctx, q, user, generics, &other)
+	require.NoError(t, err)
+
+	tagged := func(title string, tags ...uuid.UUID) uuid.UUID {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: title, URL: "https://example.com/" + title, TagIDs: tags}, clock.Now())
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(d.ID)
+	}
+	both := tagged("both", golang, other)
+	sources := tagged("sources", other, lang)
+	one := tagged("one", lang)
+
+	target, merged, err := tag.Merge(ctx, q, user, golang, []uuid.UUID{other, lang})
+	require.NoError(t, err)
+	assert.Equal(t, golang.String(), target.ID)
+	assert.Len(t, merged, 2)
+
+	for _, id := range []uuid.UUID{both, sources, one} {
+		d, err := drop.Get(ctx, q, user, id)
+		require.NoError(t, err)
+		require.Len(t, d.Tags, 1)
+		assert.Equal(t, golang.String(), d.Tags[0].ID)
+
+		es, err := drop.History(ctx, q, user, id)
+		require.NoError(t, err)
+		require.Len(t, es, 2)
+		assert.Equal(t, drop.EventTag, es[1].Kind)
+		assert.Equal(t, d.Tags, es[1].After.Tags)
+	}
+
+	_, err = tag.Get(ctx, q, user, other)
+	assert.ErrorIs(t, err, api.NoResourceError("tag", other.String()))
+	g, err := tag.Get(ctx, q, user, generics)
+	require.NoError(t, err)
+	require.NotNil(t, g.ParentID)
+	assert.Equal(t, golang.String(), *g.ParentID)
+
+	var aerr api.Error
+	_, _, err = tag.Merge(ctx, q, user, generics, []uuid.UUID{golang})
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+}
+
+func TestListCounts(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	create := func(name string) uuid.UUID {
+		tg, err := tag.Create(ctx, q, user, name)
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(tg.ID)
+	}
+	busy := create("busy")
+	quiet := create("quiet")
+	unused := create("unused")
+
+	tagged := func(title string, tags ...uuid.UUID) uuid.UUID {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{Title: title, URL: "https://example.com/" + title, TagIDs: tags}, now)
+		require.NoError(t, err)
+		return uuid.FromStringOrNil(d.ID)
+	}
+	tagged("one", busy, quiet)
+	read := tagged("two", busy)
+	_, err := drop.Move(ctx, q, user, read, drop.StatusRead, now)
+	require.NoError(t, err)
+
+	ts, err := tag.ListCounts(ctx, q, user, tag.ListOptions{Sort: tag.SortUsage}, now)
+	require.NoError(t, err)
+	require.Len(t, ts, 3)
+	assert.Equal(t, busy.String(), ts[0].ID)
+	assert.Equal(t, stats.Counts{Unread: 1, Read: 1}, *ts[0].Counts)
+	assert.Equal(t, quiet.String(), ts[1].ID)
+	assert.Equal(t, stats.Counts{Unread: 1}, *ts[1].Counts)
+	assert.Equal(t, unused.String(), ts[2].ID)
+	assert.Equal(t, stats.Counts{}, *ts[2].Counts)
+
+	ts, err = tag.ListCounts(ctx, q, user, tag.ListOptions{Unused: true}, now)
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	assert.Equal(t, unused.String(), ts[0].ID)
+
+	color := "#1e90ff"
+	tg, err := tag.Update(ctx, q, user, unused, tag.UpdateFields{Color: &color})
+	require.NoError(t, err)
+	assert.Equal(t, color, tg.Color)
+	assert.Empty(t, tg.Description)
+}
+
+func TestValidateColor(t *testing.T) {
+	for color, ok := range map[string]bool{
+		"":         true,
+		"#1e90ff":  true,
+		"#1E90FF":  true,
+		"1e90ff":   false,
+		"#fff":     false,
+		"dodger":   false,
+		"#1e90ffa": false,
+	} {
+		err := tag.UpdateBody{Color: &color}.Validate()
+		if ok {
+			assert.NoError(t, err, color)
+		} else {
+			assert.Error(t, err, color)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package view
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+)
+
+type Handler struct{}
+
+type CreateBody struct {
+	Name   string        `json:"name,omitempty"`
+	Filter drop.ListBody `json:"filter"`
+}
+
+func (b CreateBody) Validate() error {
+	if err := validateName(b.Name); err != nil {
+		return err
+	}
+	return validateFilter(b.Filter)
+}
+
+func (Handler) Create(ctx api.Context, u api.User, body CreateBody) (View, error) {
+	q := db.New(ctx.Tx)
+	return Create(ctx, q, u, body.Name, body.Filter)
+}
+
+type ListResponse struct {
+	Views []View `json:"views"`
+}
+
+func (Handler) List(ctx api.Context, u api.User) (ListResponse, error) {
+	q := db.New(ctx.Tx)
+	vs, err := List(ctx, q, u)
+	return ListResponse{Views: vs}, err
+}
+
+type UpdateBody struct {
+	ID     uuid.UUID      `json:"id,omitempty"`
+	Name   *string        `json:"name,omitempty"`
+	Filter *drop.ListBody `json:"filter,omitempty"`
+}
+
+func (b UpdateBody) Validate() error {
+	if b.Name != nil {
+		if err := validateName(*b.Name); err != nil {
+			return err
+		}
+	}
+	if b.Filter != nil {
+		return validateFilter(*b.Filter)
+	}
+	return nil
+}
+
+func (Handler) Update(ctx api.Context, u api.User, body UpdateBody) (View, error) {
+	q := db.New(ctx.Tx)
+	return Update(ctx, q, u, body.ID, UpdateFields{
+		Name:   body.Name,
+		Filter: body.Filter,
+	})
+}
+
+type DeleteBody struct {
+	ID uuid.UUID `json:"id,omitempty"`
+}
+
+func (Handler) Delete(ctx api.Context, u api.User, body DeleteBody) (View, error) {
+	q := db.New(ctx.Tx)
+	return Delete(ctx, q, u, body.ID)
+}
+
+type ListDropsBody struct {
+	ID     uuid.UUID    `json:"id,omitempty"`
+	Limit  *int32       `json:"limit,omitempty"`
+	Cursor *drop.Cursor `json:"cursor,omitempty"`
+}
+
+func (Handler) ListDrops(ctx api.Context, u api.User, body ListDropsBody) (drop.ListResponse, error) {
+	q := db.New(ctx.Tx)
+	return Drops(ctx, q, u, body.ID, body.Limit, body.Cursor, ctx.Clock.Now())
+}
+
+type NextDropBody struct {
+	ID       uuid.UUID     `json:"id,omitempty"`
+	Strategy drop.Strategy `json:"strategy,omitempty"`
+}
+
+func (b NextDropBody) Validate() error {
+	return drop.NextBody{Strategy: b.Strategy}.Validate()
+}
+
+func (Handler) NextDrop(ctx api.Context, u api.User, body NextDropBody) (drop.Drop, error) {
+	q := db.New(ctx.Tx)
+	return Next(ctx, q, u, body.ID, body.Strategy, ctx.Clock.Now())
+}
@@ -0,0 +1,215 @@
+// Package view manages saved views, which are named filters for listing
+// drops.
+package view
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+)
+
+// A View is a saved filter. Listing drops by the view is the same as listing
+// them with the filter as the body.
+type View struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Filter drop.ListBody `json:"filter"`
+}
+
+func model(v db.View) (View, error) {
+	var f drop.ListBody
+	if err := json.Unmarshal(v.Filter, &f); err != nil {
+		return View{}, err
+	}
+	return View{
+		ID:     v.ID.String(),
+		Name:   v.Name,
+		Filter: f,
+	}, nil
+}
+
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return api.ValidationError("name", name, "must not be blank")
+	}
+	return nil
+}
+
+func validateFilter(f drop.ListBody) error {
+	if f.Cursor != nil {
+		return api.ValidationError("filter.cursor", f.Cursor.String(), "views can't start at a cursor")
+	}
+	return f.Validate()
+}
+
+func Create(ctx context.Context, q db.Queryable, user api.User, name string, filter drop.ListBody) (View, error) {
+	if err := checkName(ctx, q, user, name, uuid.Nil); err != nil {
+		return View{}, err
+	}
+
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return View{}, err
+	}
+	v, err := q.ViewCreate(ctx, db.ViewCreateParams{
+		UserID: user.ID,
+		Name:   name,
+		Filter: b,
+	})
+	if err != nil {
+		return View{}, err
+	}
+	return model(v)
+}
+
+// checkName returns a duplicate error if the user has another view with the
+// name.
+func checkName(ctx context.Context, q db.Queryable, user api.User, name string, id uuid.UUID) error {
+	v, err := q.ViewFindByName(ctx, db.ViewFindByNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && v.ID == id) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := model(v)
+	if err != nil {
+		return err
+	}
+	return api.DuplicateError("view", existing.ID, &existing)
+}
+
+func List(ctx context.Context, q db.Queryable, user api.User) ([]View, error) {
+	vs, err := q.ViewList(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]View, 0, len(vs))
+	for _, v := range vs {
+		m, err := model(v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+func Get(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (View, error) {
+	v, err := q.ViewFind(ctx, db.ViewFindParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return View{}, api.NoResourceError("view", id.String())
+	}
+	if err != nil {
+		return View{}, err
+	}
+	return model(v)
+}
+
+type UpdateFields struct {
+	Name *string
+	// Filter replaces the whole filter.
+	Filter *drop.ListBody
+}
+
+// Update changes the fields of a view that are set.
+func Update(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, f UpdateFields) (View, error) {
+	v, err := Get(ctx, q, user, id)
+	if err != nil {
+		return View{}, err
+	}
+
+	if f.Name != nil {
+		if err := checkName(ctx, q, user, *f.Name, id); err != nil {
+			return View{}, err
+		}
+		v.Name = *f.Name
+	}
+	if f.Filter != nil {
+		v.Filter = *f.Filter
+	}
+
+	b, err := json.Marshal(v.Filter)
+	if err != nil {
+		return View{}, err
+	}
+	updated, err := q.ViewUpdate(ctx, db.ViewUpdateParams{
+		UserID: user.ID,
+		ID:     id,
+		Name:   v.Name,
+		Filter: b,
+	})
+	if err != nil {
+		return View{}, err
+	}
+	return model(updated)
+}
+
+func Delete(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID) (View, error) {
+	v, err := q.ViewDelete(ctx, db.ViewDeleteParams{
+		UserID: user.ID,
+		ID:     id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return View{}, api.NoResourceError("view", id.String())
+	}
+	if err != nil {
+		return View{}, err
+	}
+	return model(v)
+}
+
+// Drops lists a page of the drops in the view. The limit overrides the one in
+// the view's filter, if it's set.
+func Drops(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, limit *int32, after *drop.Cursor, now time.Time) (drop.ListResponse, error) {
+	v, err := Get(ctx, q, user, id)
+	if err != nil {
+		return drop.ListResponse{}, err
+	}
+
+	body := v.Filter
+	if limit != nil {
+		body.Limit = limit
+	}
+	body.Cursor = after
+	return drop.ListPage(ctx, q, user, body, now)
+}
+
+// Next picks the next drop in the view, using the strategy. Views without a
+// status (in the filter or the query) only pick unread drops, like Next.
+func Next(ctx context.Context, q db.Queryable, user api.User, id uuid.UUID, strategy drop.Strategy, now time.Time) (drop.Drop, error) {
+	v, err := Get(ctx, q, user, id)
+	if err != nil {
+		return drop.Drop{}, err
+	}
+
+	f := v.Filter
+	body := drop.NextBody{
+		Strategy:          strategy,
+		MaxReadingMinutes: f.MaxReadingMinutes,
+		Query:             f.Query,
+	}
+	if f.Status != drop.StatusUnknown {
+		body.Statuses = []drop.Status{f.Status}
+	}
+	if f.Tags != nil {
+		body.Tags = *f.Tags
+	}
+	return drop.NextMatching(ctx, q, user, body, now)
+}
@@ -0,0 +1,106 @@
+package view_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/db"
+	"github.com/metagram-net/firehose/drop"
+	"github.com/metagram-net/firehose/internal/apitest"
+	"github.com/metagram-net/firehose/tag"
+	"github.com/metagram-net/firehose/view"
+)
+
+func TestView(t *testing.T) {
+	var (
+		ctx   = apitest.Context(t, 500*time.Millisecond)
+		clock = apitest.Clock(t)
+		tx    = apitest.Tx(t, ctx)
+		user  = apitest.User(t, ctx, tx)
+		q     = db.New(tx)
+		now   = clock.Now()
+	)
+
+	work, err := tag.Create(ctx, q, user, "work")
+	require.NoError(t, err)
+	workID := uuid.FromStringOrNil(work.ID)
+
+	create := func(url string, tags []uuid.UUID, age time.Duration) string {
+		d, err := drop.Create(ctx, q, user, nil, drop.CreateFields{URL: url, TagIDs: tags}, now.Add(-age))
+		require.NoError(t, err)
+		return d.ID
+	}
+	first := create("https://example.com/1", []uuid.UUID{workID}, 3*time.Hour)
+	second := create("https://example.com/2", []uuid.UUID{workID}, 2*time.Hour)
+	create("https://example.com/3", nil, time.Hour)
+
+	limit := int32(1)
+	v, err := view.Create(ctx, q, user, "work", drop.ListBody{Limit: &limit, Query: "tag:work"})
+	require.NoError(t, err)
+	id := uuid.FromStringOrNil(v.ID)
+
+	_, err = view.Create(ctx, q, user, "work", drop.ListBody{})
+	var aerr api.Error
+	require.ErrorAs(t, err, &aerr)
+	assert.Equal(t, api.ErrorCode("duplicate_resource"), aerr.Code)
+
+	vs, err := view.List(ctx, q, user)
+	require.NoError(t, err)
+	assert.Equal(t, []view.View{v}, vs)
+
+	// The view's limit applies unless it's overridden.
+	res, err := view.Drops(ctx, q, user, id, nil, nil, now)
+	require.NoError(t, err)
+	require.Len(t, res.Drops, 1)
+	assert.Equal(t, first, res.Drops[0].ID)
+	require.NotNil(t, res.NextCursor)
+
+	res, err = view.Drops(ctx, q, user, id, nil, res.NextCursor, now)
+	require.NoError(t, err)
+	require.Len(t, res.Drops, 1)
+	assert.Equal(t, second, res.Drops[0].ID)
+	assert.Nil(t, res.NextCursor)
+
+	d, err := view.Next(ctx, q, user, id, drop.StrategyNewest, now)
+	require.NoError(t, err)
+	assert.Equal(t, second, d.ID)
+
+	name := "everything"
+	v, err = view.Update(ctx, q, user, id, view.UpdateFields{
+		Name:   &name,
+		Filter: &drop.ListBody{Status: drop.StatusUnread},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "everything", v.Name)
+
+	res, err = view.Drops(ctx, q, user, id, nil, nil, now)
+	require.NoError(t, err)
+	assert.Len(t, res.Drops, 3)
+
+	_, err = view.Delete(ctx, q, user, id)
+	require.NoError(t, err)
+	_, err = view.Get(ctx, q, user, id)
+	assert.ErrorIs(t, err, api.NoResourceError("view", id.String()))
+}
+
+func TestCreateBodyValidate(t *testing.T) {
+	assert.NoError(t, view.CreateBody{Name: "work", Filter: drop.ListBody{Query: "tag:work"}}.Validate())
+
+	tests := map[string]view.CreateBody{
+		"blank name": {Name: " "},
+		"cursor":     {Name: "work", Filter: drop.ListBody{Cursor: &drop.Cursor{}}},
+		"bad query":  {Name: "work", Filter: drop.ListBody{Query: "status:nope"}},
+	}
+	for name, b := range tests {
+		t.Run(name, func(t *testing.T) {
+			var aerr api.Error
+			require.ErrorAs(t, b.Validate(), &aerr)
+			assert.Equal(t, api.ErrorCode("validation_error"), aerr.Code)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package worker runs recurring background jobs alongside the API server.
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/metagram-net/firehose/api"
+	"github.com/metagram-net/firehose/clock"
+)
+
+// A Job does one unit of work in its own transaction, which is committed if
+// the job succeeds. It returns true if there might be more work waiting, in
+// which case it runs again right away instead of waiting for the next tick.
+type Job func(ctx api.Context) (more bool, err error)
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+type Worker struct {
+	log  *zap.Logger
+	db   *sql.DB
+	jobs []job
+}
+
+func New(log *zap.Logger, db *sql.DB) *Worker {
+	return &Worker{log: log, db: db}
+}
+
+// Every registers a job to run on an interval. Jobs must be registered before
+// calling Run.
+func (w *Worker) Every(name string, interval time.Duration, run Job) {
+	w.jobs = append(w.jobs, job{name, interval, run})
+}
+
+// Run runs all the jobs until ctx is canceled, and then waits for any that are
+// in progress to finish.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range w.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			w.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context, j job) {
+	log := w.log.With(zap.String("job", j.name))
+	log.Info("Starting job", zap.Duration("interval", j.interval))
+
+	t := time.NewTicker(j.interval)
+	defer t.Stop()
+	for {
+		// Keep going while there's more to do, but give up on errors until
+		// the next tick so a failing job doesn't spin.
+		for ctx.Err() == nil && w.runOnce(ctx, log, j) {
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Stopping job")
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (w *Worker) runOnce(ctx context.Context, log *zap.Logger, j job) bool {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("Could not start transaction", zap.Error(err))
+		return false
+	}
+
+	c := api.Context{
+		Context: ctx,
+		Log:     log,
+		Tx:      tx,
+		Clock:   clock.Freeze(time.Now()),
+	}
+	more, err := j.run(c)
+	if err != nil {
+		log.Error("Job failed", zap.Error(err))
+		if err := tx.Rollback(); err != nil {
+			log.Error("Could not roll back transaction", zap.Error(err))
+		}
+		return false
+	}
+	if err := c.Close(); err != nil {
+		log.Error("Could not commit transaction", zap.Error(err))
+		return false
+	}
+	return more
+}